@@ -2,11 +2,13 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 
 	"github.com/dcshock/prov-go/pkg/provenance"
@@ -114,7 +116,9 @@ func loadCmd(args []string) {
 	fs := flag.NewFlagSet("load", flag.ExitOnError)
 	filePath := fs.String("file", "", "Path to base64 file containing []RegistryEntry")
 	mnemonicFile := fs.String("mnemonic-file", "", "Path to mnemonic file for signer key")
-	network := fs.String("network", "mainnet", "Network: mainnet or testnet")
+	network := fs.String("network", "mainnet", "Network: mainnet, testnet or localnet")
+	configFile := fs.String("config", "", "Path to a TOML/YAML/JSON chain config; overrides -network")
+	fromEnv := fs.Bool("env", false, "Read the chain config from PROV_* environment variables; overrides -network")
 	_ = fs.Parse(args)
 
 	if *filePath == "" && len(fs.Args()) > 0 {
@@ -159,18 +163,22 @@ func loadCmd(args []string) {
 
 	// Choose blockchain configuration
 	var cfg *provenance.BlockchainConfig
-	switch *network {
-	case "mainnet":
-		cfg = provenance.NewMainnetConfig()
-	case "testnet":
-		cfg = provenance.NewTestnetConfig()
+	switch {
+	case *configFile != "":
+		cfg, err = provenance.LoadConfig(*configFile)
+	case *fromEnv:
+		cfg, err = provenance.ConfigFromEnv()
 	default:
-		log.Fatalf("invalid network %q: must be mainnet or testnet", *network)
+		cfg, err = provenance.NewNetworkConfig(provenance.Network(*network))
+	}
+	if err != nil {
+		log.Fatalf("invalid chain config: %v", err)
 	}
 
 	// The NewProvenanceClient helper will read the mnemonic file, derive the key,
-	// and populate the ProvenanceClient with signer details.
-	p, err := provenance.NewProvenanceClient(cfg, mnemonicFile)
+	// and populate the ProvenanceClient with signer details. Broadcasts are logged
+	// through the standard logger.
+	p, err := provenance.NewProvenanceClient(cfg, mnemonicFile, provenance.WithLogger(slog.Default()))
 	if err != nil {
 		log.Fatalf("error creating provenance client: %v", err)
 	}
@@ -183,14 +191,14 @@ func loadCmd(args []string) {
 			end = len(entries)
 		}
 		batch := entries[i:end]
-		resp, err := p.RegistryBulkUpdate(batch)
+		resp, err := p.RegistryBulkUpdateContext(context.Background(), batch)
 		if err != nil {
 			log.Fatalf("error executing RegistryBulkUpdate (batch %d-%d): %v", i+1, end, err)
 		}
 
 		// Wait for the tx to be included in a block
 		log.Printf("Waiting for tx %s to be included in a block...\n", resp.TxResponse.TxHash)
-		txResp, err := p.WaitOnTx(resp.TxResponse.TxHash)
+		txResp, err := p.WaitOnTx(context.Background(), resp.TxResponse.TxHash)
 		if err != nil {
 			log.Fatalf("error waiting on tx: %v", err)
 		}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -9,18 +10,24 @@ import (
 )
 
 func main() {
-	if len(os.Args) != 2 {
-		log.Fatalf("usage: %s <tx-hash>", os.Args[0])
+	if len(os.Args) != 2 && len(os.Args) != 3 {
+		log.Fatalf("usage: %s <tx-hash> [rpc-url]", os.Args[0])
 	}
 	txHash := os.Args[1]
 
+	// With a CometBFT RPC url, resolve as soon as the tx is included instead of on the next poll.
+	var opts []provenance.WaitOption
+	if len(os.Args) == 3 {
+		opts = append(opts, provenance.WithTxSubscription(os.Args[2]))
+	}
+
 	p, err := provenance.NewProvenanceClient(provenance.NewMainnetConfig(), nil)
 	if err != nil {
 		log.Fatalf("error creating provenance client: %v", err)
 	}
 	defer p.Close()
 
-	resp, err := p.WaitOnTx(txHash)
+	resp, err := p.WaitOnTx(context.Background(), txHash, opts...)
 	if err != nil {
 		log.Fatalf("wait on tx: %v", err)
 	}
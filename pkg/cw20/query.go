@@ -15,7 +15,8 @@ type QueryClient struct {
 	addr string
 }
 
-// NewQueryClient builds a CW20 query client. conn is typically provenance.ProvenanceClient.Grpc.Conn.
+// NewQueryClient builds a CW20 query client. conn is typically provenance.ProvenanceClient.Grpc, or
+// the Grpc of an AtHeight view to query as of a past block height.
 func NewQueryClient(conn grpc.ClientConnInterface, cw20ContractAddr string) *QueryClient {
 	return &QueryClient{
 		wasm: wasmtypes.NewQueryClient(conn),
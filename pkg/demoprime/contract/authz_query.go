@@ -2,7 +2,7 @@ package contract
 
 import (
 	"context"
-	"strings"
+	"errors"
 	"time"
 
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
@@ -15,14 +15,7 @@ import (
 const msgTypeURLCommitFunds = "/provenance.exchange.v1.MsgCommitFundsRequest"
 
 func isAuthzNotFound(err error) bool {
-	if err == nil {
-		return false
-	}
-	// gRPC / SDK variants
-	if strings.Contains(strings.ToLower(err.Error()), "authorization not found") {
-		return true
-	}
-	return false
+	return errors.Is(provenance.ClassifyError(err), provenance.ErrNotFound)
 }
 
 func grantStillValid(g *authztypes.Grant, now time.Time) bool {
@@ -43,16 +43,26 @@ func (b *BaseClient) PrimarySignerAddress() string {
 	return b.Prov.Address
 }
 
-// RequireSigner returns an error if the provenance client has no private key.
+// RequireSigner returns an error if the provenance client has no signer.
 func (b *BaseClient) RequireSigner() error {
-	if b.Prov == nil || b.Prov.PrivKey == nil {
+	if b.Prov == nil || b.Prov.Signer == nil {
 		return fmt.Errorf("demoprime/contract: provenance client has no signer")
 	}
 	return nil
 }
 
 func (b *BaseClient) authzQuery() authztypes.QueryClient {
-	return authztypes.NewQueryClient(b.Prov.Grpc.Conn)
+	return authztypes.NewQueryClient(b.Prov.Grpc)
+}
+
+// AtHeight returns a read-only copy of the client whose queries are answered as of block height h; see
+// provenance.ProvenanceClient.AtHeight.
+func (b *BaseClient) AtHeight(h int64) *BaseClient {
+	return &BaseClient{
+		Prov:                  b.Prov.AtHeight(h),
+		ContractAddr:          b.ContractAddr,
+		RelativeTimeoutHeight: b.RelativeTimeoutHeight,
+	}
 }
 
 // CurrentHeight returns the latest committed block height (Kotlin pbcHandler.currentHeight).
@@ -80,25 +90,41 @@ func (b *BaseClient) CalculateTimeoutHeight(ctx context.Context) (int64, error)
 	return h + b.RelativeTimeoutHeight, nil
 }
 
-// BroadcastMsgs signs (via provenance.SignTx), broadcasts, waits for inclusion, and returns the tx response.
-// Fails if TxResponse.Code != 0.
-func (b *BaseClient) BroadcastMsgs(ctx context.Context, msgs []sdk.Msg) (*sdk.TxResponse, error) {
+// DefaultTxOptions returns the tx options applied to every broadcast: a timeout height of
+// CalculateTimeoutHeight when RelativeTimeoutHeight is positive.
+func (b *BaseClient) DefaultTxOptions(ctx context.Context) ([]provenance.TxOption, error) {
+	if b.RelativeTimeoutHeight == 0 {
+		return nil, nil
+	}
+	h, err := b.CalculateTimeoutHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("demoprime/contract: timeout height: %w", err)
+	}
+	return []provenance.TxOption{provenance.WithTimeoutHeight(uint64(h))}, nil
+}
+
+// BroadcastMsgs signs and broadcasts (via provenance.SignAndBroadcast), waits for inclusion, and returns the tx response.
+// opts are applied after DefaultTxOptions, so callers may override the timeout height. Fails if TxResponse.Code != 0.
+func (b *BaseClient) BroadcastMsgs(ctx context.Context, msgs []sdk.Msg, opts ...provenance.TxOption) (*sdk.TxResponse, error) {
 	if err := b.RequireSigner(); err != nil {
 		return nil, err
 	}
-	seq := b.Prov.NextSequence()
-	txBz, err := b.Prov.SignTx(msgs, b.Prov.PrivKey.Bytes(), b.Prov.AccountNumber, seq, 0)
+	txOpts, err := b.DefaultTxOptions(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("demoprime/contract: sign tx: %w", err)
+		return nil, err
 	}
-	bcast, err := b.Prov.BroadcastTx(txBz)
+	bcast, err := b.Prov.SignAndBroadcast(ctx, msgs, append(txOpts, opts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("demoprime/contract: broadcast: %w", err)
 	}
 	if bcast.TxResponse == nil || bcast.TxResponse.TxHash == "" {
 		return nil, fmt.Errorf("demoprime/contract: empty broadcast response")
 	}
-	got, err := b.Prov.WaitOnTx(bcast.TxResponse.TxHash)
+	if bcast.TxResponse.Code != 0 {
+		txr := bcast.TxResponse
+		return txr, fmt.Errorf("demoprime/contract: %w", provenance.NewTxFailedError(txr, true))
+	}
+	got, err := b.Prov.WaitOnTx(ctx, bcast.TxResponse.TxHash)
 	if err != nil {
 		return nil, fmt.Errorf("demoprime/contract: wait on tx: %w", err)
 	}
@@ -107,7 +133,7 @@ func (b *BaseClient) BroadcastMsgs(ctx context.Context, msgs []sdk.Msg) (*sdk.Tx
 		return nil, fmt.Errorf("demoprime/contract: nil tx in get-tx response")
 	}
 	if txr.Code != 0 {
-		return txr, fmt.Errorf("demoprime/contract: tx failed code=%d codespace=%s raw_log=%s", txr.Code, txr.Codespace, txr.RawLog)
+		return txr, fmt.Errorf("demoprime/contract: %w", provenance.NewTxFailedError(txr, false))
 	}
 	return txr, nil
 }
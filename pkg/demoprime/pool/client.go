@@ -9,6 +9,7 @@ import (
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
 	tendermint "cosmossdk.io/api/cosmos/base/tendermint/v1beta1"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/x/authz"
 
 	"github.com/dcshock/prov-go/pkg/demoprime/contract"
@@ -28,8 +29,14 @@ func NewClient(prov *provenance.ProvenanceClient, contractAddr string) *Client {
 	return &Client{BaseClient: contract.NewBaseClient(prov, contractAddr)}
 }
 
+// AtHeight returns a read-only copy of the client whose queries, such as GetState and GetReserve, are
+// answered as of block height h.
+func (c *Client) AtHeight(h int64) *Client {
+	return &Client{BaseClient: c.BaseClient.AtHeight(h)}
+}
+
 func (c *Client) wasmQuery() wasmtypes.QueryClient {
-	return wasmtypes.NewQueryClient(c.Prov.Grpc.Conn)
+	return wasmtypes.NewQueryClient(c.Prov.Grpc)
 }
 
 func (c *Client) querySmartContract(ctx context.Context, queryJSON []byte) ([]byte, error) {
@@ -134,19 +141,22 @@ func (c *Client) broadcastAndWait(ctx context.Context, msgs []sdk.Msg) (*sdk.TxR
 	if err := c.RequireSigner(); err != nil {
 		return nil, nil, err
 	}
-	seq := c.Prov.NextSequence()
-	txBz, err := c.Prov.SignTx(msgs, c.Prov.PrivKey.Bytes(), c.Prov.AccountNumber, seq, 0)
+	txOpts, err := c.DefaultTxOptions(ctx)
 	if err != nil {
-		return nil, nil, fmt.Errorf("pool: sign tx: %w", err)
+		return nil, nil, err
 	}
-	bcast, err := c.Prov.BroadcastTx(txBz)
+	bcast, err := c.Prov.SignAndBroadcast(ctx, msgs, txOpts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("pool: broadcast: %w", err)
 	}
 	if bcast.TxResponse == nil || bcast.TxResponse.TxHash == "" {
 		return nil, nil, fmt.Errorf("pool: empty broadcast response")
 	}
-	got, err := c.Prov.WaitOnTx(bcast.TxResponse.TxHash)
+	if bcast.TxResponse.Code != 0 {
+		txr := bcast.TxResponse
+		return txr, nil, fmt.Errorf("pool: %w", provenance.NewTxFailedError(txr, true))
+	}
+	got, err := c.Prov.WaitOnTx(ctx, bcast.TxResponse.TxHash)
 	if err != nil {
 		return nil, nil, fmt.Errorf("pool: wait on tx: %w", err)
 	}
@@ -155,7 +165,7 @@ func (c *Client) broadcastAndWait(ctx context.Context, msgs []sdk.Msg) (*sdk.TxR
 		return nil, nil, fmt.Errorf("pool: nil tx in get-tx response")
 	}
 	if txr.Code != 0 {
-		return txr, nil, fmt.Errorf("pool: tx failed code=%d codespace=%s raw_log=%s", txr.Code, txr.Codespace, txr.RawLog)
+		return txr, nil, fmt.Errorf("pool: %w", provenance.NewTxFailedError(txr, false))
 	}
 	block, err := c.fetchBlock(ctx, txr.Height)
 	if err != nil {
@@ -189,9 +199,16 @@ func (c *Client) executeOnBehalfOf(ctx context.Context, granterAddress string, m
 		Msg:      wasmtypes.RawContractMessage(msgJSON),
 		Funds:    funds,
 	}
-	grantee := sdk.MustAccAddressFromBech32(c.Prov.Address)
-	exec := authz.NewMsgExec(grantee, []sdk.Msg{inner})
-	return c.broadcastAndWait(ctx, []sdk.Msg{&exec})
+	// authz.NewMsgExec formats the grantee with the global SDK prefix; use the client's own address.
+	if _, err := c.Prov.ParseAddress(c.Prov.Address); err != nil {
+		return nil, nil, fmt.Errorf("pool: grantee: %w", err)
+	}
+	msgs, err := txtypes.SetMsgs([]sdk.Msg{inner})
+	if err != nil {
+		return nil, nil, fmt.Errorf("pool: pack exec msgs: %w", err)
+	}
+	exec := &authz.MsgExec{Grantee: c.Prov.Address, Msgs: msgs}
+	return c.broadcastAndWait(ctx, []sdk.Msg{exec})
 }
 
 // --- Executes (direct) ---
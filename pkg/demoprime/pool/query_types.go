@@ -1,11 +1,50 @@
 package pool
 
+import (
+	"fmt"
+	"strings"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/dcshock/prov-go/pkg/provenance"
+)
+
 // Denom matches pool contract denom JSON (short keys).
 type Denom struct {
 	Name      string `json:"n"`
 	Precision int    `json:"p"`
 }
 
+// FormatAmount formats a base unit amount of a pool response, such as LendResponseV1.Amount or
+// ReserveStateResponseV1.TotalLiquidity, in the display unit r has registered for denom. The pool only
+// names the base unit, so a denom r does not know is shifted by denom.Precision decimal places and
+// given without a unit, e.g. "2.5".
+func FormatAmount(r *provenance.DenomRegistry, amount string, denom Denom) (string, error) {
+	n, ok := sdkmath.NewIntFromString(amount)
+	if !ok {
+		return "", fmt.Errorf("invalid %s amount %q", denom.Name, amount)
+	}
+	if _, ok := r.Lookup(denom.Name); ok {
+		return r.FormatAmount(n, denom.Name), nil
+	}
+	if denom.Precision <= 0 {
+		return n.String(), nil
+	}
+
+	sign, digits := "", n.String()
+	if n.IsNegative() {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) <= denom.Precision {
+		digits = strings.Repeat("0", denom.Precision-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-denom.Precision], strings.TrimRight(digits[len(digits)-denom.Precision:], "0")
+	if frac != "" {
+		whole += "." + frac
+	}
+	return sign + whole, nil
+}
+
 // AssetRequirementV1 is a collateral line in query responses.
 type AssetRequirementV1 struct {
 	AssetID string `json:"asset_id"`
@@ -0,0 +1,39 @@
+package pool
+
+import (
+	"testing"
+
+	"github.com/dcshock/prov-go/pkg/provenance"
+)
+
+func TestFormatAmount(t *testing.T) {
+	t.Parallel()
+	r := provenance.NewDenomRegistry()
+	if err := r.Register(provenance.DenomInfo{
+		Base:    "uusdc",
+		Display: "usdc",
+		Units:   []provenance.DenomUnit{{Denom: "usdc", Exponent: 6}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	lending := Denom{Name: "uusdc", Precision: 6}
+
+	got, err := FormatAmount(r, "2500000", lending)
+	if err != nil || got != "2.5usdc" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	// A denom the registry does not know is shifted by the pool's precision.
+	for amount, want := range map[string]string{"2500000": "2.5", "7": "0.000007", "-3000000": "-3"} {
+		got, err := FormatAmount(r, amount, Denom{Name: "uylds", Precision: 6})
+		if err != nil || got != want {
+			t.Fatalf("unregistered %s: got %q, %v; want %q", amount, got, err, want)
+		}
+	}
+	if got, _ := FormatAmount(r, "42", Denom{Name: "widget"}); got != "42" {
+		t.Fatalf("no precision: got %q", got)
+	}
+
+	if _, err := FormatAmount(r, "2.5", lending); err == nil {
+		t.Fatal("expected an error for a non-integer amount")
+	}
+}
@@ -0,0 +1,128 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// accountRegistry holds the named signing accounts of a client. The client and all of its views share
+// one registry.
+type accountRegistry struct {
+	mu       sync.Mutex
+	accounts map[string]*ProvenanceClient
+}
+
+func (c *ProvenanceClient) registry() *accountRegistry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accounts == nil {
+		c.accounts = &accountRegistry{}
+	}
+	return c.accounts
+}
+
+// view returns a client that shares c's connection, codecs and settings but none of its account or
+// lazily built query clients. Closing the view does not close c's connection.
+func (c *ProvenanceClient) view() *ProvenanceClient {
+	return &ProvenanceClient{
+		Grpc:                 c.Grpc.view(c.Grpc),
+		BcConfig:             c.BcConfig,
+		Cdc:                  c.Cdc,
+		TxConfig:             c.TxConfig,
+		AddressCodec:         c.AddressCodec,
+		Pool:                 c.Pool,
+		BroadcastRetryPolicy: c.BroadcastRetryPolicy,
+		CallPolicy:           c.CallPolicy,
+		Logger:               c.Logger,
+		Progress:             c.Progress,
+		accounts:             c.registry(),
+		denoms:               c.Denoms(),
+	}
+}
+
+// AddAccount registers signer under name and returns the client for it: a view of c that signs with
+// signer and tracks the account's number and sequence separately from c and every other account, while
+// sharing c's connection. Select the account by calling tx methods, or building contract and pool
+// clients, on the returned client or on Account(name). The account must exist on chain.
+//
+// The client stands in for a per-call account selector such as a TxOption: helpers like Send and the
+// marker builders put the signer's address in the msgs they build before any TxOption is read, and
+// each account needs its own sequence and node pin, so the selection has to happen on the client.
+func (c *ProvenanceClient) AddAccount(ctx context.Context, name string, signer Signer) (*ProvenanceClient, error) {
+	if name == "" {
+		return nil, fmt.Errorf("account name is required")
+	}
+
+	acct := c.view()
+	acct.Signer = signer
+	address, err := acct.FormatAddress(signer.Address())
+	if err != nil {
+		return nil, fmt.Errorf("error encoding signer address: %w", err)
+	}
+	acct.Address = address
+	// The account's sequence-dependent calls stay on one node, whichever node the others use.
+	acct.Grpc.pinKey = address
+
+	reg := c.registry()
+	reg.mu.Lock()
+	_, exists := reg.accounts[name]
+	reg.mu.Unlock()
+	if exists {
+		return nil, fmt.Errorf("account %q: %w", name, ErrAlreadyExists)
+	}
+
+	acct.AccountNumber, _, err = acct.ResetSequenceContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting account info for %q: %w", name, err)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if _, exists := reg.accounts[name]; exists {
+		return nil, fmt.Errorf("account %q: %w", name, ErrAlreadyExists)
+	}
+	if reg.accounts == nil {
+		reg.accounts = make(map[string]*ProvenanceClient)
+	}
+	reg.accounts[name] = acct
+	return acct, nil
+}
+
+// Account returns the client for the account registered under name, or an error wrapping ErrNotFound.
+func (c *ProvenanceClient) Account(name string) (*ProvenanceClient, error) {
+	reg := c.registry()
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	acct, ok := reg.accounts[name]
+	if !ok {
+		return nil, fmt.Errorf("account %q: %w", name, ErrNotFound)
+	}
+	return acct, nil
+}
+
+// Accounts returns the names of the registered accounts in sorted order.
+func (c *ProvenanceClient) Accounts() []string {
+	reg := c.registry()
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	names := make([]string, 0, len(reg.accounts))
+	for name := range reg.accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RemoveAccount unregisters the account under name. Clients already returned for it keep working.
+func (c *ProvenanceClient) RemoveAccount(name string) {
+	reg := c.registry()
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	delete(reg.accounts, name)
+}
@@ -0,0 +1,95 @@
+package provenance
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// accountNode serves base accounts whose account number and sequence are set per address.
+type accountNode struct {
+	*authtypes.UnimplementedQueryServer
+	accounts map[string]authtypes.BaseAccount
+}
+
+func (n accountNode) Account(_ context.Context, req *authtypes.QueryAccountRequest) (*authtypes.QueryAccountResponse, error) {
+	acc, ok := n.accounts[req.Address]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "account %s not found", req.Address)
+	}
+	a, err := codectypes.NewAnyWithValue(&acc)
+	if err != nil {
+		return nil, err
+	}
+	return &authtypes.QueryAccountResponse{Account: a}, nil
+}
+
+func TestAccounts(t *testing.T) {
+	t.Parallel()
+	conf := NewLocalnetConfig()
+	c := &ProvenanceClient{BcConfig: conf}
+
+	var signers []Signer
+	node := accountNode{accounts: map[string]authtypes.BaseAccount{}}
+	for i, m := range []string{offlineTestMnemonic, "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong"} {
+		s, err := NewMnemonicSigner(conf, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, _ := c.FormatAddress(s.Address())
+		node.accounts[addr] = authtypes.BaseAccount{Address: addr, AccountNumber: uint64(10 + i), Sequence: uint64(100 * (i + 1))}
+		signers = append(signers, s)
+	}
+
+	c.Grpc = serveTestNode(t, func(srv *grpc.Server) {
+		authtypes.RegisterQueryServer(srv, node)
+	})
+
+	hot, err := c.AddAccount(context.Background(), "hot", signers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops, err := c.AddAccount(context.Background(), "ops", signers[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hot.AccountNumber != 10 || hot.Sequence != 100 || ops.AccountNumber != 11 || ops.Sequence != 200 {
+		t.Fatalf("hot %d/%d, ops %d/%d", hot.AccountNumber, hot.Sequence, ops.AccountNumber, ops.Sequence)
+	}
+
+	// Accounts report the shared connection's health and status.
+	hot.Grpc.CheckHealth(context.Background())
+	if got := hot.Grpc.Status(); len(got) != 1 || got[0].URI != c.Grpc.Conn.Target() {
+		t.Fatalf("unexpected status %+v", got)
+	}
+
+	// Each account reserves its own sequences.
+	hot.NextSequence()
+	if got, _ := c.Account("hot"); got != hot || hot.Sequence != 101 || ops.Sequence != 200 {
+		t.Fatalf("after reserving on hot: hot %d, ops %d", hot.Sequence, ops.Sequence)
+	}
+
+	if _, err := c.AddAccount(context.Background(), "hot", signers[1]); !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("duplicate name: %v", err)
+	}
+	if _, err := c.Account("cold"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unknown name: %v", err)
+	}
+	if names := ops.Accounts(); !slices.Equal(names, []string{"hot", "ops"}) {
+		t.Fatalf("accounts: %v", names)
+	}
+
+	// Accounts share the client's connection, so closing one leaves it open.
+	hot.Close()
+	c.RemoveAccount("hot")
+	if _, err := c.AddAccount(context.Background(), "hot", signers[0]); err != nil {
+		t.Fatalf("re-adding after close: %v", err)
+	}
+}
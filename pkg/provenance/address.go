@@ -0,0 +1,48 @@
+package provenance
+
+import (
+	"fmt"
+
+	"cosmossdk.io/core/address"
+	"github.com/cosmos/cosmos-sdk/client"
+	addresscodec "github.com/cosmos/cosmos-sdk/codec/address"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewProvenanceClient leaves the global SDK config (sdk.GetConfig) alone so that clients for different
+// networks can live in the same process. sdk.AccAddress.String() formats with the global prefix, so
+// the client encodes addresses with its own AddressCodec instead.
+
+// addressCodec returns the client's AddressCodec. Clients built without NewProvenanceClient fall
+// back to the configured prefix, then to the global one.
+func (c *ProvenanceClient) addressCodec() address.Codec {
+	if c.AddressCodec != nil {
+		return c.AddressCodec
+	}
+	if c.BcConfig != nil {
+		return addresscodec.NewBech32Codec(c.BcConfig.AddressPrefix())
+	}
+	return addresscodec.NewBech32Codec(sdk.GetConfig().GetBech32AccountAddrPrefix())
+}
+
+// txConfig returns the client's TxConfig, or one without an address codec if it has none.
+func (c *ProvenanceClient) txConfig() client.TxConfig {
+	if c.TxConfig != nil {
+		return c.TxConfig
+	}
+	return NewTxConfig()
+}
+
+// FormatAddress encodes addr with the client's bech32 prefix.
+func (c *ProvenanceClient) FormatAddress(addr sdk.AccAddress) (string, error) {
+	return c.addressCodec().BytesToString(addr)
+}
+
+// ParseAddress decodes a bech32 address, which must use the client's prefix.
+func (c *ProvenanceClient) ParseAddress(bech string) (sdk.AccAddress, error) {
+	bz, err := c.addressCodec().StringToBytes(bech)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", bech, err)
+	}
+	return bz, nil
+}
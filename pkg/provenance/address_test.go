@@ -0,0 +1,63 @@
+package provenance
+
+import (
+	"strings"
+	"testing"
+
+	addresscodec "github.com/cosmos/cosmos-sdk/codec/address"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+func TestClientsWithDifferentPrefixes(t *testing.T) {
+	t.Parallel()
+	addr := sdk.AccAddress(make([]byte, 20))
+	mainnet := &ProvenanceClient{BcConfig: NewMainnetConfig()}
+	testnet := &ProvenanceClient{BcConfig: NewTestnetConfig()}
+
+	pb, err := mainnet.FormatAddress(addr)
+	if err != nil || !strings.HasPrefix(pb, "pb1") {
+		t.Fatalf("mainnet address %q: %v", pb, err)
+	}
+	tp, err := testnet.FormatAddress(addr)
+	if err != nil || !strings.HasPrefix(tp, "tp1") {
+		t.Fatalf("testnet address %q: %v", tp, err)
+	}
+
+	if _, err := mainnet.ParseAddress(tp); err == nil {
+		t.Fatal("mainnet client accepted a testnet address")
+	}
+	got, err := testnet.ParseAddress(tp)
+	if err != nil || !got.Equals(addr) {
+		t.Fatalf("parse %s: %v", tp, err)
+	}
+}
+
+func TestTxOptionsFeeAddresses(t *testing.T) {
+	t.Parallel()
+	ac := addresscodec.NewBech32Codec("tp")
+	granter, _ := ac.BytesToString(make([]byte, 20))
+	payer, _ := ac.BytesToString(append(make([]byte, 19), 1))
+
+	txConfig := NewTxConfig()
+	txBuilder := txConfig.NewTxBuilder()
+	if err := NewTxOptions(WithFeeGranter(granter), WithFeePayer(payer)).apply(txBuilder, ac); err != nil {
+		t.Fatal(err)
+	}
+
+	txBz, err := txConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded txtypes.Tx
+	if err := decoded.Unmarshal(txBz); err != nil {
+		t.Fatal(err)
+	}
+	if fee := decoded.AuthInfo.Fee; fee.Granter != granter || fee.Payer != payer {
+		t.Fatalf("fee addresses: got granter %q payer %q", fee.Granter, fee.Payer)
+	}
+
+	if err := NewTxOptions(WithFeeGranter("pb1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqnrql8a")).apply(txConfig.NewTxBuilder(), ac); err == nil {
+		t.Fatal("expected error for fee granter with another prefix")
+	}
+}
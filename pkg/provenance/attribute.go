@@ -19,17 +19,30 @@ type Attribute struct {
 	JsonValue string
 }
 
-func (c *ProvenanceClient) AddAttributes(attrs []Attribute) (chan *tx.BroadcastTxResponse, chan error) {
+// AddAttributes is AddAttributesContext with a background context.
+//
+// Deprecated: use AddAttributesContext.
+func (c *ProvenanceClient) AddAttributes(attrs []Attribute, opts ...TxOption) (chan *tx.BroadcastTxResponse, chan error) {
+	return c.AddAttributesContext(context.Background(), attrs, opts...)
+}
+
+// AddAttributesContext adds JSON attributes that don't exist yet, in transactions of up to 75 attributes.
+// Responses and errors are sent on the returned channels, which are closed once every attribute has
+// been handled. When ctx is done, no further attributes are checked or sent.
+func (c *ProvenanceClient) AddAttributesContext(ctx context.Context, attrs []Attribute, opts ...TxOption) (chan *tx.BroadcastTxResponse, chan error) {
 	// Go routine to add attributes in chunks.
 	attrAddChan := make(chan *attrtypes.MsgAddAttributeRequest, len(attrs))
 
 	attrRespChan := make(chan *tx.BroadcastTxResponse)
 	attrErrChan := make(chan error)
 
-	renderer := Renderer{
-		first:   true,
-		Count:   0,
-		Current: "",
+	progress := c.progress()
+
+	sendErr := func(err error) {
+		select {
+		case attrErrChan <- err:
+		case <-ctx.Done():
+		}
 	}
 
 	go func() {
@@ -49,32 +62,29 @@ func (c *ProvenanceClient) AddAttributes(attrs []Attribute) (chan *tx.BroadcastT
 				// We still need to send the last batch of attributes
 				closed = true
 			} else {
-				renderer.SetCurrent(fmt.Sprintf("Adding attribute: %s %s", attr.Name, attr.Account))
+				progress.SetCurrent(fmt.Sprintf("Adding attribute: %s %s", attr.Name, attr.Account))
 				buff = append(buff, attr)
 			}
 
 			// Use a buff size of 75 to limit our chance of hitting the 4m max gas limit
 			if len(buff) == 75 || (closed && len(buff) > 0) {
-				// Attribute additions require 10_000_000_000 additional fee
-				txFee := int64(10_000_000_000) * int64(len(buff))
-				txBz, err := c.SignTx(buff, c.PrivKey.Bytes(), c.AccountNumber, c.NextSequence(), txFee)
+				batch := buff
 
 				// Clear the buffer
 				buff = []sdk.Msg{}
 
+				// Attribute msg fees are included by the fee estimator
+				progress.SetCurrent(fmt.Sprintf("Sending tx with %d attributes", len(batch)))
+				resp, err := c.SignAndBroadcast(ctx, batch, opts...)
 				if err != nil {
-					attrErrChan <- err
+					sendErr(err)
 					continue
 				}
 
-				renderer.SetCurrent(fmt.Sprintf("Sending tx with %d attributes", len(buff)))
-				resp, err := c.BroadcastTx(txBz)
-				if err != nil {
-					attrErrChan <- err
-					continue
+				select {
+				case attrRespChan <- resp:
+				case <-ctx.Done():
 				}
-
-				attrRespChan <- resp
 			}
 		}
 	}()
@@ -82,20 +92,23 @@ func (c *ProvenanceClient) AddAttributes(attrs []Attribute) (chan *tx.BroadcastT
 	go func() {
 		defer close(attrAddChan)
 
-		renderer.SetCurrent(fmt.Sprintf("Adding attributes: %d", len(attrs)))
+		progress.SetCurrent(fmt.Sprintf("Adding attributes: %d", len(attrs)))
 
 		for _, attr := range attrs {
-			renderer.IncrementCount()
-			renderer.SetCurrent(fmt.Sprintf("Processing: %s %s", attr.Name, attr.Acct))
+			if ctx.Err() != nil {
+				return
+			}
+			progress.IncrementCount()
+			progress.SetCurrent(fmt.Sprintf("Processing: %s %s", attr.Name, attr.Acct))
 
-			attrs, err := c.GetAttributes(context.Background(), attr.Name, attr.Acct)
+			attrs, err := c.GetAttributes(ctx, attr.Name, attr.Acct)
 			if err != nil {
-				attrErrChan <- err
+				sendErr(err)
 				continue
 			}
 
 			if len(attrs) > 0 {
-				attrErrChan <- fmt.Errorf("attribute already exists %s %s", attr.Name, attr.Acct)
+				sendErr(fmt.Errorf("attribute %s %s: %w", attr.Name, attr.Acct, ErrAlreadyExists))
 				continue
 			}
 
@@ -342,6 +355,7 @@ func (c *ProvenanceClient) GetAttributedAccountsStream(ctx context.Context, name
 	return accountsChan, errChan
 }
 
+// Renderer is a Progress that redraws a processed count and the current step on stdout using ANSI escapes.
 type Renderer struct {
 	first bool
 	mu    sync.Mutex
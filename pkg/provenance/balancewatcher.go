@@ -0,0 +1,398 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	abci "github.com/cometbft/cometbft/abci/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// BalanceDelta is a change in the balance of one denom held by a watched address.
+type BalanceDelta struct {
+	Address string
+	Denom   string
+	// Amount is positive for funds received and negative for funds spent.
+	Amount sdkmath.Int
+	Height int64
+	// TxHash is the transaction that made the change. It is empty for a correction found by a balance
+	// snapshot, which covers changes made outside transactions, such as staking rewards, as well as
+	// any that were missed.
+	TxHash string
+}
+
+// BalanceWatcherOptions tunes a BalanceWatcher. Zero fields fall back to DefaultBalanceWatcherOptions.
+type BalanceWatcherOptions struct {
+	// FromHeight is the first block to inspect. 0 starts after the latest block. To resume, pass one
+	// more than the Height of the watcher being replaced.
+	FromHeight int64
+	// PollInterval is how often the node is checked for new blocks.
+	PollInterval time.Duration
+	// SnapshotInterval is how often the balances of every watched address are queried and compared
+	// with the balances the deltas add up to.
+	SnapshotInterval time.Duration
+	// Buffer is the number of deltas held for a slow reader before the watcher stops to wait for it.
+	Buffer int
+}
+
+var DefaultBalanceWatcherOptions = BalanceWatcherOptions{
+	PollInterval:     time.Second,
+	SnapshotInterval: 5 * time.Minute,
+	Buffer:           100,
+}
+
+// BalanceWatcher follows new blocks and emits the balance changes of a set of addresses. Changes
+// are read from the coin_spent and coin_received events of every transaction in a block, netted per
+// transaction, address and denom. Transactions without those events fall back to their transfer
+// events. Blocks are searched with GetTxsEvent, so the node must index transactions.
+//
+// Balances are also queried every SnapshotInterval at the last inspected height. A difference from
+// what the deltas add up to is emitted as a delta without a TxHash, so the sum of all deltas since the
+// first snapshot always converges on the real balance.
+type BalanceWatcher struct {
+	c       *ProvenanceClient
+	opts    BalanceWatcherOptions
+	watched map[string]bool
+	deltas  chan BalanceDelta
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu     sync.Mutex
+	height int64
+	err    error
+
+	// Owned by run. balances is nil until the first snapshot succeeds.
+	balances map[string]map[string]sdkmath.Int
+}
+
+// NewBalanceWatcher starts a BalanceWatcher for addresses. It runs until ctx is done or Close is
+// called, and then closes Deltas.
+func (c *ProvenanceClient) NewBalanceWatcher(ctx context.Context, addresses []string, opts BalanceWatcherOptions) (*BalanceWatcher, error) {
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no addresses to watch")
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultBalanceWatcherOptions.PollInterval
+	}
+	if opts.SnapshotInterval <= 0 {
+		opts.SnapshotInterval = DefaultBalanceWatcherOptions.SnapshotInterval
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultBalanceWatcherOptions.Buffer
+	}
+
+	watched := make(map[string]bool, len(addresses))
+	for _, addr := range addresses {
+		if _, err := c.ParseAddress(addr); err != nil {
+			return nil, err
+		}
+		watched[addr] = true
+	}
+
+	from := opts.FromHeight
+	if from <= 0 {
+		latest, err := c.latestBlockHeight(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting latest block: %w", err)
+		}
+		from = latest + 1
+	}
+
+	w := &BalanceWatcher{
+		c:       c,
+		opts:    opts,
+		watched: watched,
+		deltas:  make(chan BalanceDelta, opts.Buffer),
+		done:    make(chan struct{}),
+		height:  from - 1,
+	}
+	ctx, w.cancel = context.WithCancel(ctx)
+	go w.run(ctx)
+	return w, nil
+}
+
+// Deltas receives the balance changes of the watched addresses in block order. It is closed when the
+// watcher stops.
+func (w *BalanceWatcher) Deltas() <-chan BalanceDelta {
+	return w.deltas
+}
+
+// Height returns the last block whose deltas have all been sent on Deltas.
+func (w *BalanceWatcher) Height() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.height
+}
+
+// Err returns why the watcher stopped, or nil while it is running.
+func (w *BalanceWatcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Close stops the watcher and waits for it to finish.
+func (w *BalanceWatcher) Close() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *BalanceWatcher) run(ctx context.Context) {
+	defer close(w.done)
+	defer close(w.deltas)
+
+	log := w.c.logger().With("watched", len(w.watched))
+	log.Debug("balance watcher started", "from_height", w.Height()+1)
+
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+
+	// Take the first snapshot right away so deltas can be checked against it.
+	var lastSnapshot time.Time
+	for {
+		if time.Since(lastSnapshot) >= w.opts.SnapshotInterval {
+			if err := w.snapshot(ctx); err != nil {
+				// Try again on the next poll.
+				log.Warn("balance snapshot failed", "height", w.Height(), "error", err)
+			} else {
+				lastSnapshot = time.Now()
+			}
+		}
+
+		if err := w.catchUp(ctx); err != nil && ctx.Err() == nil {
+			log.Warn("error following blocks", "height", w.Height(), "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			w.mu.Lock()
+			w.err = ctx.Err()
+			w.mu.Unlock()
+			log.Debug("balance watcher stopped", "height", w.Height(), "error", ctx.Err())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// catchUp emits the deltas of every block up to the latest one.
+func (w *BalanceWatcher) catchUp(ctx context.Context) error {
+	latest, err := w.c.latestBlockHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	for h := w.Height() + 1; h <= latest; h++ {
+		deltas, err := w.blockDeltas(ctx, h)
+		if err != nil {
+			return fmt.Errorf("block %d: %w", h, err)
+		}
+		for _, d := range deltas {
+			if !w.send(ctx, d) {
+				return ctx.Err()
+			}
+			if w.balances != nil {
+				w.apply(d)
+			}
+		}
+
+		w.mu.Lock()
+		w.height = h
+		w.mu.Unlock()
+	}
+	return nil
+}
+
+// blockDeltas returns the deltas of every transaction in the block at height.
+func (w *BalanceWatcher) blockDeltas(ctx context.Context, height int64) ([]BalanceDelta, error) {
+	txClient := txtypes.NewServiceClient(w.c.Grpc)
+
+	var deltas []BalanceDelta
+	for page, seen := uint64(1), 0; ; page++ {
+		res, err := txClient.GetTxsEvent(ctx, &txtypes.GetTxsEventRequest{
+			Query: fmt.Sprintf("tx.height=%d", height),
+			Page:  page,
+			Limit: 100,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, txr := range res.TxResponses {
+			deltas = append(deltas, w.txDeltas(txr)...)
+		}
+		seen += len(res.TxResponses)
+		if len(res.TxResponses) == 0 || uint64(seen) >= res.Total {
+			return deltas, nil
+		}
+	}
+}
+
+// txDeltas nets the coin movements of a transaction per watched address and denom, in the order
+// they first appear.
+func (w *BalanceWatcher) txDeltas(txr *sdk.TxResponse) []BalanceDelta {
+	var deltas []BalanceDelta
+	index := map[[2]string]int{}
+	add := func(addr, amount string, sign int64) {
+		if !w.watched[addr] {
+			return
+		}
+		coins, err := sdk.ParseCoinsNormalized(amount)
+		if err != nil {
+			w.c.logger().Warn("could not parse event amount", "tx_hash", txr.TxHash, "amount", amount, "error", err)
+			return
+		}
+		for _, coin := range coins {
+			key := [2]string{addr, coin.Denom}
+			i, ok := index[key]
+			if !ok {
+				i = len(deltas)
+				index[key] = i
+				deltas = append(deltas, BalanceDelta{
+					Address: addr,
+					Denom:   coin.Denom,
+					Amount:  sdkmath.ZeroInt(),
+					Height:  txr.Height,
+					TxHash:  txr.TxHash,
+				})
+			}
+			deltas[i].Amount = deltas[i].Amount.Add(coin.Amount.MulRaw(sign))
+		}
+	}
+
+	coinEvents := false
+	for _, ev := range txr.Events {
+		switch ev.Type {
+		case "coin_spent":
+			coinEvents = true
+			add(eventAttr(ev, "spender"), eventAttr(ev, "amount"), -1)
+		case "coin_received":
+			coinEvents = true
+			add(eventAttr(ev, "receiver"), eventAttr(ev, "amount"), 1)
+		}
+	}
+	if !coinEvents {
+		for _, ev := range txr.Events {
+			if ev.Type == "transfer" {
+				add(eventAttr(ev, "sender"), eventAttr(ev, "amount"), -1)
+				add(eventAttr(ev, "recipient"), eventAttr(ev, "amount"), 1)
+			}
+		}
+	}
+
+	nonZero := deltas[:0]
+	for _, d := range deltas {
+		if !d.Amount.IsZero() {
+			nonZero = append(nonZero, d)
+		}
+	}
+	return nonZero
+}
+
+// snapshot queries the balances of every watched address at the last inspected height and emits a
+// correction for each difference from the tracked balances. The first snapshot only records them.
+func (w *BalanceWatcher) snapshot(ctx context.Context) error {
+	height := w.Height()
+	view := w.c.AtHeight(height)
+	defer view.Close()
+
+	current := make(map[string]map[string]sdkmath.Int, len(w.watched))
+	for addr := range w.watched {
+		balances, err := view.GetBalances(ctx, addr)
+		if err != nil {
+			return fmt.Errorf("error getting balances of %s: %w", addr, err)
+		}
+		current[addr] = make(map[string]sdkmath.Int, len(balances))
+		for _, coin := range balances {
+			current[addr][coin.Denom] = coin.Amount
+		}
+	}
+
+	if w.balances == nil {
+		w.balances = current
+		return nil
+	}
+
+	for addr, have := range current {
+		tracked := w.balances[addr]
+		var denoms []string
+		for denom := range have {
+			denoms = append(denoms, denom)
+		}
+		for denom := range tracked {
+			if _, ok := have[denom]; !ok {
+				denoms = append(denoms, denom)
+			}
+		}
+		sort.Strings(denoms)
+
+		for _, denom := range denoms {
+			diff := amountOf(have, denom).Sub(amountOf(tracked, denom))
+			if diff.IsZero() {
+				continue
+			}
+			w.c.logger().Info("balance snapshot correction", "address", addr, "denom", denom, "amount", diff, "height", height)
+			d := BalanceDelta{Address: addr, Denom: denom, Amount: diff, Height: height}
+			if !w.send(ctx, d) {
+				return ctx.Err()
+			}
+		}
+	}
+	w.balances = current
+	return nil
+}
+
+func amountOf(balances map[string]sdkmath.Int, denom string) sdkmath.Int {
+	if amount, ok := balances[denom]; ok {
+		return amount
+	}
+	return sdkmath.ZeroInt()
+}
+
+// apply adds d to the tracked balances.
+func (w *BalanceWatcher) apply(d BalanceDelta) {
+	if w.balances[d.Address] == nil {
+		w.balances[d.Address] = map[string]sdkmath.Int{}
+	}
+	if have, ok := w.balances[d.Address][d.Denom]; ok {
+		w.balances[d.Address][d.Denom] = have.Add(d.Amount)
+	} else {
+		w.balances[d.Address][d.Denom] = d.Amount
+	}
+}
+
+func (w *BalanceWatcher) send(ctx context.Context, d BalanceDelta) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case w.deltas <- d:
+		return true
+	}
+}
+
+// eventAttr returns the value of the first attribute of ev with the given key.
+func eventAttr(ev abci.Event, key string) string {
+	for _, attr := range ev.Attributes {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// latestBlockHeight returns the height of the latest block.
+func (c *ProvenanceClient) latestBlockHeight(ctx context.Context) (int64, error) {
+	res, err := c.GetLatestBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if header := res.GetSdkBlock().GetHeader(); header != nil {
+		return header.Height, nil
+	}
+	return res.GetBlock().GetHeader().GetHeight(), nil
+}
@@ -0,0 +1,142 @@
+package provenance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tmtypes "cosmossdk.io/api/cosmos/base/tendermint/v1beta1"
+	abci "github.com/cometbft/cometbft/abci/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func coinEvent(typ string, attrs ...string) abci.Event {
+	ev := abci.Event{Type: typ}
+	for i := 0; i < len(attrs); i += 2 {
+		ev.Attributes = append(ev.Attributes, abci.EventAttribute{Key: attrs[i], Value: attrs[i+1]})
+	}
+	return ev
+}
+
+// watchNode is at height 12. The watched address holds 100nhash at height 10, spends 12nhash in
+// block 11, is sent 7usd in block 12 and earns 3nhash outside any transaction.
+type watchNode struct {
+	tmtypes.UnimplementedServiceServer
+	watched string
+}
+
+func (watchNode) GetLatestBlock(context.Context, *tmtypes.GetLatestBlockRequest) (*tmtypes.GetLatestBlockResponse, error) {
+	return &tmtypes.GetLatestBlockResponse{SdkBlock: &tmtypes.Block{Header: &tmtypes.Header{Height: 12}}}, nil
+}
+
+type watchTxService struct {
+	*txtypes.UnimplementedServiceServer
+	node watchNode
+}
+
+func (s watchTxService) GetTxsEvent(_ context.Context, req *txtypes.GetTxsEventRequest) (*txtypes.GetTxsEventResponse, error) {
+	a := s.node.watched
+	var txs []*sdk.TxResponse
+	switch strings.TrimPrefix(req.Query, "tx.height=") {
+	case "11":
+		txs = []*sdk.TxResponse{{Height: 11, TxHash: "AA", Events: []abci.Event{
+			coinEvent("coin_spent", "spender", a, "amount", "2nhash"),
+			coinEvent("coin_received", "receiver", "tp1feecollector", "amount", "2nhash"),
+			coinEvent("coin_spent", "spender", a, "amount", "10nhash"),
+			coinEvent("coin_received", "receiver", "tp1other", "amount", "10nhash"),
+			coinEvent("transfer", "sender", a, "recipient", "tp1other", "amount", "10nhash"),
+		}}}
+	case "12":
+		txs = []*sdk.TxResponse{
+			{Height: 12, TxHash: "BB", Events: []abci.Event{
+				coinEvent("transfer", "sender", "tp1other", "recipient", a, "amount", "7usd"),
+			}},
+			{Height: 12, TxHash: "CC", Events: []abci.Event{
+				coinEvent("coin_spent", "spender", "tp1other", "amount", "1nhash"),
+			}},
+		}
+	}
+	return &txtypes.GetTxsEventResponse{TxResponses: txs, Total: uint64(len(txs))}, nil
+}
+
+type watchBank struct {
+	*banktypes.UnimplementedQueryServer
+}
+
+func (watchBank) AllBalances(ctx context.Context, _ *banktypes.QueryAllBalancesRequest) (*banktypes.QueryAllBalancesResponse, error) {
+	balances := sdk.NewCoins(sdk.NewInt64Coin("nhash", 91), sdk.NewInt64Coin("usd", 7))
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(grpctypes.GRPCBlockHeightHeader)) > 0 && md.Get(grpctypes.GRPCBlockHeightHeader)[0] == "10" {
+		balances = sdk.NewCoins(sdk.NewInt64Coin("nhash", 100))
+	}
+	return &banktypes.QueryAllBalancesResponse{Balances: balances, Pagination: &query.PageResponse{}}, nil
+}
+
+func TestBalanceWatcher(t *testing.T) {
+	t.Parallel()
+	addrs, err := DeriveAddresses(NewLocalnetConfig(), offlineTestMnemonic, Derivation{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := watchNode{watched: addrs[0]}
+
+	conn := serveTestNode(t, func(srv *grpc.Server) {
+		tmtypes.RegisterServiceServer(srv, node)
+		txtypes.RegisterServiceServer(srv, watchTxService{node: node})
+		banktypes.RegisterQueryServer(srv, watchBank{})
+	})
+	c := &ProvenanceClient{Grpc: conn, BcConfig: NewLocalnetConfig()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	w, err := c.NewBalanceWatcher(ctx, addrs, BalanceWatcherOptions{
+		FromHeight:       11,
+		PollInterval:     10 * time.Millisecond,
+		SnapshotInterval: time.Nanosecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		denom  string
+		amount int64
+		height int64
+		txHash string
+	}{
+		{"nhash", -12, 11, "AA"},
+		{"usd", 7, 12, "BB"},
+		{"nhash", 3, 12, ""},
+	}
+	for i, exp := range want {
+		d, ok := <-w.Deltas()
+		if !ok {
+			t.Fatalf("deltas closed early: %v", w.Err())
+		}
+		if d.Address != addrs[0] || d.Denom != exp.denom || d.Amount.Int64() != exp.amount || d.Height != exp.height || d.TxHash != exp.txHash {
+			t.Fatalf("delta %d: %+v", i, d)
+		}
+	}
+
+	// The corrected balances match the node, so nothing more is sent.
+	select {
+	case d := <-w.Deltas():
+		t.Fatalf("unexpected delta %+v", d)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	w.Close()
+	if _, ok := <-w.Deltas(); ok {
+		t.Fatal("deltas still open after Close")
+	}
+	if w.Height() != 12 || !errors.Is(w.Err(), context.Canceled) {
+		t.Fatalf("stopped at height %d with %v", w.Height(), w.Err())
+	}
+}
@@ -153,3 +153,309 @@ func (c *ProvenanceClient) GetBalance(ctx context.Context, address, denom string
 
 	return *res.Balance, nil
 }
+
+// GetSpendableBalances retrieves the balances of the given address that are not locked up by vesting
+// or holds. It handles pagination automatically and will return all balances across multiple pages.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - address: The blockchain address to query spendable balances for
+//   - opts: Optional gRPC call options (e.g., grpc.WaitForReady, custom timeouts, etc.)
+//
+// Returns:
+//   - []sdk.Coin: A slice containing all spendable balances for the address
+//   - error: Returns an error if the query fails or context is cancelled
+func (c *ProvenanceClient) GetSpendableBalances(ctx context.Context, address string, opts ...grpc.CallOption) ([]sdk.Coin, error) {
+	balances := []sdk.Coin{}
+	nextKey := []byte(nil)
+	for {
+		res, err := (*c.BankClient()).SpendableBalances(ctx, &banktypes.QuerySpendableBalancesRequest{
+			Address:    address,
+			Pagination: &query.PageRequest{Key: nextKey, Limit: 100},
+		}, opts...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+
+		balances = append(balances, res.Balances...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return balances, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// GetTotalSupply retrieves the total supply of every denom on chain. It handles pagination
+// automatically and will return the supply across multiple pages.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - opts: Optional gRPC call options (e.g., grpc.WaitForReady, custom timeouts, etc.)
+//
+// Returns:
+//   - sdk.Coins: The total supply of each denom
+//   - error: Returns an error if the query fails or context is cancelled
+func (c *ProvenanceClient) GetTotalSupply(ctx context.Context, opts ...grpc.CallOption) (sdk.Coins, error) {
+	supply := sdk.Coins{}
+	nextKey := []byte(nil)
+	for {
+		res, err := (*c.BankClient()).TotalSupply(ctx, &banktypes.QueryTotalSupplyRequest{
+			Pagination: &query.PageRequest{Key: nextKey, Limit: 100},
+		}, opts...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+
+		supply = append(supply, res.Supply...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return supply, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// GetSupplyOf retrieves the total supply of a single denom.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - denom: The denomination to query (e.g., "nhash", "usd")
+//   - opts: Optional gRPC call options (e.g., grpc.WaitForReady, custom timeouts, etc.)
+//
+// Returns:
+//   - sdk.Coin: The supply of denom. A denom that does not exist has a supply of 0.
+//   - error: Returns an error if the query fails or context is cancelled
+func (c *ProvenanceClient) GetSupplyOf(ctx context.Context, denom string, opts ...grpc.CallOption) (sdk.Coin, error) {
+	res, err := (*c.BankClient()).SupplyOf(ctx, &banktypes.QuerySupplyOfRequest{Denom: denom}, opts...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return sdk.Coin{}, ctx.Err()
+		}
+		return sdk.Coin{}, err
+	}
+	return res.Amount, nil
+}
+
+// GetDenomMetadata retrieves the bank metadata of a denom: its description, display name and units.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - denom: The base denomination to query
+//   - opts: Optional gRPC call options (e.g., grpc.WaitForReady, custom timeouts, etc.)
+//
+// Returns:
+//   - *banktypes.Metadata: The metadata of denom
+//   - error: Returns an error if the query fails or context is cancelled. A denom without metadata
+//     returns an error matching ErrNotFound.
+func (c *ProvenanceClient) GetDenomMetadata(ctx context.Context, denom string, opts ...grpc.CallOption) (*banktypes.Metadata, error) {
+	res, err := (*c.BankClient()).DenomMetadata(ctx, &banktypes.QueryDenomMetadataRequest{Denom: denom}, opts...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return &res.Metadata, nil
+}
+
+// GetAllDenomsMetadata retrieves the bank metadata of every denom that has any. It handles pagination
+// automatically and will return the metadata across multiple pages.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - opts: Optional gRPC call options (e.g., grpc.WaitForReady, custom timeouts, etc.)
+//
+// Returns:
+//   - []banktypes.Metadata: A slice containing the metadata of each denom
+//   - error: Returns an error if the query fails or context is cancelled
+func (c *ProvenanceClient) GetAllDenomsMetadata(ctx context.Context, opts ...grpc.CallOption) ([]banktypes.Metadata, error) {
+	metadatas := []banktypes.Metadata{}
+	nextKey := []byte(nil)
+	for {
+		res, err := (*c.BankClient()).DenomsMetadata(ctx, &banktypes.QueryDenomsMetadataRequest{
+			Pagination: &query.PageRequest{Key: nextKey, Limit: 100},
+		}, opts...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+
+		metadatas = append(metadatas, res.Metadatas...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return metadatas, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// GetDenomOwners retrieves every account holding the given denom, with its balance, and returns
+// them as a slice. It handles pagination automatically and will return all owners across multiple
+// pages. The function respects context cancellation and will return ctx.Err() if the context
+// is cancelled before completion.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - denom: The denomination to query owners of
+//   - opts: Optional gRPC call options (e.g., grpc.WaitForReady, custom timeouts, etc.)
+//
+// Returns:
+//   - []*banktypes.DenomOwner: A slice containing each holder's address and balance
+//   - error: Returns an error if the query fails or context is cancelled
+func (c *ProvenanceClient) GetDenomOwners(ctx context.Context, denom string, opts ...grpc.CallOption) ([]*banktypes.DenomOwner, error) {
+	ownersChan, errChan := c.GetDenomOwnersStream(ctx, denom, opts...)
+
+	owners := []*banktypes.DenomOwner{}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case owner, ok := <-ownersChan:
+			if !ok {
+				return owners, nil
+			}
+			owners = append(owners, owner)
+		case err := <-errChan:
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// GetDenomOwnersStream retrieves the holders of the given denom and streams them through channels.
+// This function is useful for processing denoms with large numbers of holders incrementally without
+// loading them all into memory at once. It handles pagination automatically and sends owners as
+// they are retrieved from the blockchain.
+//
+// The function returns two channels:
+//   - ownersChan: Receives owner values as they are retrieved. The channel is closed
+//     when all owners have been sent or an error occurs.
+//   - errChan: Receives any errors that occur during retrieval. If an error is sent,
+//     the ownersChan will be closed and no more owners will be sent.
+//
+// The function respects context cancellation. If the context is cancelled, ctx.Err()
+// will be sent on errChan and both channels will be closed.
+//
+// The caller must read from both channels until they are closed to prevent goroutine leaks.
+// If the context is cancelled or an error occurs, the caller should stop reading from
+// ownersChan and read the error from errChan.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - denom: The denomination to query owners of
+//   - opts: Optional gRPC call options (e.g., grpc.WaitForReady, custom timeouts, etc.)
+//
+// Returns:
+//   - chan *banktypes.DenomOwner: Channel that receives owner values. Closed when complete or on error.
+//   - chan error: Channel that receives errors. Closed when the goroutine exits.
+func (c *ProvenanceClient) GetDenomOwnersStream(ctx context.Context, denom string, opts ...grpc.CallOption) (chan *banktypes.DenomOwner, chan error) {
+	pageBufferSize := uint64(100) // Match the page size of the client request.
+
+	ownersChan := make(chan *banktypes.DenomOwner, pageBufferSize)
+	errChan := make(chan error, 1) // Buffer of 1 to prevent blocking the goroutine.
+
+	go func() {
+		defer close(ownersChan)
+		defer close(errChan)
+
+		nextKey := []byte(nil)
+		for {
+			res, err := (*c.BankClient()).DenomOwners(ctx, &banktypes.QueryDenomOwnersRequest{
+				Denom: denom,
+				Pagination: &query.PageRequest{
+					Key:        nextKey,
+					Limit:      pageBufferSize,
+					CountTotal: false,
+				},
+			}, opts...)
+
+			if err != nil {
+				if ctx.Err() != nil {
+					errChan <- ctx.Err()
+					return
+				}
+				errChan <- err
+				return
+			}
+
+			for _, owner := range res.DenomOwners {
+				select {
+				case <-ctx.Done():
+					errChan <- ctx.Err()
+					return
+				case ownersChan <- owner:
+				}
+			}
+
+			if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+				break
+			}
+			nextKey = res.Pagination.NextKey
+		}
+	}()
+
+	return ownersChan, errChan
+}
+
+// GetBankParams retrieves the bank module parameters.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - opts: Optional gRPC call options (e.g., grpc.WaitForReady, custom timeouts, etc.)
+//
+// Returns:
+//   - banktypes.Params: The bank module parameters, including whether sends are enabled by default
+//   - error: Returns an error if the query fails or context is cancelled
+func (c *ProvenanceClient) GetBankParams(ctx context.Context, opts ...grpc.CallOption) (banktypes.Params, error) {
+	res, err := (*c.BankClient()).Params(ctx, &banktypes.QueryParamsRequest{}, opts...)
+	if err != nil {
+		if ctx.Err() != nil {
+			return banktypes.Params{}, ctx.Err()
+		}
+		return banktypes.Params{}, err
+	}
+	return res.Params, nil
+}
+
+// GetSendEnabled retrieves the send-enabled entries of the given denoms, or of every denom that has
+// one when none are given. Denoms without an entry fall back to the DefaultSendEnabled bank param and
+// are left out of the result. It handles pagination automatically.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - denoms: The denominations to query; empty to list every entry
+//   - opts: Optional gRPC call options (e.g., grpc.WaitForReady, custom timeouts, etc.)
+//
+// Returns:
+//   - []*banktypes.SendEnabled: A slice containing each denom's send-enabled entry
+//   - error: Returns an error if the query fails or context is cancelled
+func (c *ProvenanceClient) GetSendEnabled(ctx context.Context, denoms []string, opts ...grpc.CallOption) ([]*banktypes.SendEnabled, error) {
+	entries := []*banktypes.SendEnabled{}
+	nextKey := []byte(nil)
+	for {
+		req := &banktypes.QuerySendEnabledRequest{Denoms: denoms}
+		if len(denoms) == 0 {
+			req.Pagination = &query.PageRequest{Key: nextKey, Limit: 100}
+		}
+		res, err := (*c.BankClient()).SendEnabled(ctx, req, opts...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+
+		entries = append(entries, res.SendEnabled...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return entries, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
@@ -0,0 +1,89 @@
+package provenance
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bankNode pages supply and owners one entry at a time, keyed by the index of the next entry.
+type bankNode struct {
+	*banktypes.UnimplementedQueryServer
+	supply sdk.Coins
+	owners []*banktypes.DenomOwner
+}
+
+func bankPage(req *query.PageRequest, n int) (int, *query.PageResponse) {
+	i := 0
+	if len(req.GetKey()) > 0 {
+		i, _ = strconv.Atoi(string(req.Key))
+	}
+	res := &query.PageResponse{}
+	if i+1 < n {
+		res.NextKey = []byte(strconv.Itoa(i + 1))
+	}
+	return i, res
+}
+
+func (n bankNode) TotalSupply(_ context.Context, req *banktypes.QueryTotalSupplyRequest) (*banktypes.QueryTotalSupplyResponse, error) {
+	i, p := bankPage(req.Pagination, len(n.supply))
+	return &banktypes.QueryTotalSupplyResponse{Supply: n.supply[i : i+1], Pagination: p}, nil
+}
+
+func (n bankNode) DenomOwners(_ context.Context, req *banktypes.QueryDenomOwnersRequest) (*banktypes.QueryDenomOwnersResponse, error) {
+	i, p := bankPage(req.Pagination, len(n.owners))
+	return &banktypes.QueryDenomOwnersResponse{DenomOwners: n.owners[i : i+1], Pagination: p}, nil
+}
+
+func (n bankNode) DenomMetadata(_ context.Context, req *banktypes.QueryDenomMetadataRequest) (*banktypes.QueryDenomMetadataResponse, error) {
+	return nil, status.Errorf(codes.NotFound, "client metadata for denom %s", req.Denom)
+}
+
+func TestBankQueries(t *testing.T) {
+	t.Parallel()
+	node := bankNode{
+		supply: sdk.NewCoins(sdk.NewInt64Coin("nhash", 100), sdk.NewInt64Coin("usd", 5)),
+		owners: []*banktypes.DenomOwner{
+			{Address: "tp1a", Balance: sdk.NewInt64Coin("usd", 3)},
+			{Address: "tp1b", Balance: sdk.NewInt64Coin("usd", 1)},
+			{Address: "tp1c", Balance: sdk.NewInt64Coin("usd", 1)},
+		},
+	}
+
+	conn := serveTestNode(t, func(srv *grpc.Server) {
+		banktypes.RegisterQueryServer(srv, node)
+	})
+	c := &ProvenanceClient{Grpc: conn}
+	ctx := context.Background()
+
+	supply, err := c.GetTotalSupply(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !supply.Equal(node.supply) {
+		t.Fatalf("supply %s, want %s", supply, node.supply)
+	}
+
+	owners, err := c.GetDenomOwners(ctx, "usd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	held := sdk.NewInt64Coin("usd", 0)
+	for _, o := range owners {
+		held = held.Add(o.Balance)
+	}
+	if len(owners) != 3 || owners[2].Address != "tp1c" || !held.IsEqual(sdk.NewInt64Coin("usd", 5)) {
+		t.Fatalf("owners %v hold %s", owners, held)
+	}
+
+	if _, err := c.GetDenomMetadata(ctx, "usd"); status.Code(err) != codes.NotFound {
+		t.Fatalf("metadata: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// Send sends amount from the client's address to toAddress.
+func (c *ProvenanceClient) Send(ctx context.Context, toAddress string, amount sdk.Coins, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	if _, err := c.ParseAddress(toAddress); err != nil {
+		return nil, err
+	}
+	if !amount.IsValid() || amount.IsZero() {
+		return nil, fmt.Errorf("invalid send amount %q", amount)
+	}
+
+	msg := &banktypes.MsgSend{
+		FromAddress: c.Address,
+		ToAddress:   toAddress,
+		Amount:      amount,
+	}
+	return c.SignAndBroadcast(ctx, []sdk.Msg{msg}, opts...)
+}
+
+// MultiSend sends to every output from the client's address in a single transaction.
+func (c *ProvenanceClient) MultiSend(ctx context.Context, outputs []banktypes.Output, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	msg, err := c.newMultiSend(outputs)
+	if err != nil {
+		return nil, err
+	}
+	return c.SignAndBroadcast(ctx, []sdk.Msg{msg}, opts...)
+}
+
+// newMultiSend builds a MsgMultiSend paying outputs from the client's address, which is the single
+// input the bank module allows.
+func (c *ProvenanceClient) newMultiSend(outputs []banktypes.Output) (*banktypes.MsgMultiSend, error) {
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("multi-send needs at least one output")
+	}
+
+	total := sdk.NewCoins()
+	for _, out := range outputs {
+		if _, err := c.ParseAddress(out.Address); err != nil {
+			return nil, err
+		}
+		if !out.Coins.IsValid() || out.Coins.IsZero() {
+			return nil, fmt.Errorf("invalid amount %q for %s", out.Coins, out.Address)
+		}
+		total = total.Add(out.Coins...)
+	}
+
+	return &banktypes.MsgMultiSend{
+		Inputs:  []banktypes.Input{{Address: c.Address, Coins: total}},
+		Outputs: outputs,
+	}, nil
+}
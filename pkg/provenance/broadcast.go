@@ -0,0 +1,167 @@
+package provenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// RetryPolicy bounds how many times an operation is attempted and how long to wait between attempts.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts.
+	MaxBackoff time.Duration
+	// Multiplier grows the wait after each attempt.
+	Multiplier float64
+}
+
+// DefaultBroadcastRetryPolicy retries sequence mismatches up to four times, waiting 500ms, 1s, 2s and 4s.
+var DefaultBroadcastRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+}
+
+// Backoff returns the wait before the given attempt, where attempt 1 is the first retry.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= p.Multiplier
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(d)
+}
+
+// BroadcastOption configures the request BroadcastTx sends.
+type BroadcastOption func(*txtypes.BroadcastTxRequest)
+
+// WithBroadcastMode selects BROADCAST_MODE_SYNC (the default) or BROADCAST_MODE_ASYNC.
+func WithBroadcastMode(mode txtypes.BroadcastMode) BroadcastOption {
+	return func(req *txtypes.BroadcastTxRequest) {
+		req.Mode = mode
+	}
+}
+
+// WithBroadcastRetryPolicy sets the policy SignAndBroadcast uses to retry sequence mismatches.
+func WithBroadcastRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *ProvenanceClient) {
+		c.BroadcastRetryPolicy = p
+	}
+}
+
+// SignAndBroadcast reserves the next sequence, signs msgs and broadcasts the result. When either the
+// simulation or CheckTx rejects the transaction for an incorrect account sequence, the sequence is
+// resynchronized to the one the chain expects and the transaction is re-signed and re-broadcast
+// according to BroadcastRetryPolicy. Any other CheckTx failure hands the sequence back, since a
+// rejected transaction does not consume it, and is returned to the caller without retrying.
+func (c *ProvenanceClient) SignAndBroadcast(ctx context.Context, msgs []sdk.Msg, opts ...TxOption) (*txtypes.BroadcastTxResponse, error) {
+	policy := c.BroadcastRetryPolicy
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		// mismatch is the chain's description of the sequence mismatch to recover from.
+		var mismatch string
+
+		seq := c.NextSequence()
+		txBz, err := c.SignTxContext(ctx, msgs, c.AccountNumber, seq, opts...)
+		if err != nil {
+			if !isSequenceMismatch(err) || attempt >= policy.MaxAttempts {
+				// Nothing was broadcast, so the reserved sequence can be handed out again.
+				c.ReleaseSequence(seq)
+				return nil, fmt.Errorf("error creating tx: %w", err)
+			}
+			mismatch = err.Error()
+		} else {
+			resp, err := c.BroadcastTxContext(ctx, txBz)
+			if err != nil {
+				// The node may or may not have accepted the tx, so the local sequence can't be trusted,
+				// including when ctx was cancelled mid-broadcast.
+				if _, _, rerr := c.ResetSequenceContext(context.WithoutCancel(ctx)); rerr != nil {
+					c.logger().Warn("error resyncing sequence after failed broadcast", "error", rerr)
+				}
+				return nil, fmt.Errorf("error broadcasting transaction: %w", err)
+			}
+
+			txr := resp.TxResponse
+			if txr == nil || txr.Code == 0 {
+				return resp, nil
+			}
+
+			if !isSequenceMismatchCode(txr.Codespace, txr.Code) {
+				// A tx rejected by CheckTx does not consume its sequence. If later sequences were
+				// reserved since, their own broadcasts resync.
+				c.ReleaseSequence(seq)
+				return resp, nil
+			}
+			if attempt >= policy.MaxAttempts {
+				if _, err := c.resyncSequence(ctx, txr.RawLog); err != nil {
+					c.logger().Warn("error resyncing sequence", "error", err)
+				}
+				return resp, nil
+			}
+			mismatch = txr.RawLog
+		}
+
+		expected, err := c.resyncSequence(ctx, mismatch)
+		if err != nil {
+			return nil, fmt.Errorf("error resyncing sequence: %w", err)
+		}
+
+		backoff := policy.Backoff(attempt)
+		c.logger().Warn("account sequence mismatch, retrying",
+			"sequence", seq,
+			"expected", expected,
+			"attempt", attempt,
+			"max_attempts", policy.MaxAttempts,
+			"backoff", backoff,
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+var expectedSequenceRegex = regexp.MustCompile(`account sequence mismatch, expected (\d+)`)
+
+// resyncSequence sets the client's sequence to the one a sequence mismatch error or CheckTx log says
+// the chain expects. That count includes the signer's transactions still in the mempool, which the
+// committed account state does not, so the account is only queried when msg does not say.
+func (c *ProvenanceClient) resyncSequence(ctx context.Context, msg string) (uint64, error) {
+	if m := expectedSequenceRegex.FindStringSubmatch(msg); m != nil {
+		if seq, err := strconv.ParseUint(m[1], 10, 64); err == nil {
+			c.mu.Lock()
+			c.Sequence = seq
+			c.mu.Unlock()
+			return seq, nil
+		}
+	}
+	_, seq, err := c.ResetSequenceContext(ctx)
+	return seq, err
+}
+
+// isSequenceMismatch reports whether err is the SDK's incorrect account sequence error.
+func isSequenceMismatch(err error) bool {
+	return errors.Is(ClassifyError(err), ErrSequenceMismatch)
+}
+
+// isSequenceMismatchCode reports whether an ABCI codespace/code pair is the SDK's incorrect account sequence error.
+func isSequenceMismatchCode(codespace string, code uint32) bool {
+	return abciError(codespace, code) == ErrSequenceMismatch
+}
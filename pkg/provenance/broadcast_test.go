@@ -0,0 +1,88 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+	p := RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Multiplier: 2}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, w := range want {
+		if got := p.Backoff(i + 1); got != w {
+			t.Fatalf("attempt %d: got %s want %s", i+1, got, w)
+		}
+	}
+}
+
+func TestReleaseSequence(t *testing.T) {
+	t.Parallel()
+	c := &ProvenanceClient{Sequence: 7}
+	a := c.NextSequence()
+	b := c.NextSequence()
+	if c.ReleaseSequence(a) {
+		t.Fatalf("released %d while %d was still reserved", a, b)
+	}
+	if !c.ReleaseSequence(b) || c.Sequence != b {
+		t.Fatalf("release %d: sequence is %d", b, c.Sequence)
+	}
+}
+
+func TestIsSequenceMismatch(t *testing.T) {
+	t.Parallel()
+	err := fmt.Errorf("simulate tx failed: rpc error: code = Unknown desc = account sequence mismatch, expected 12, got 11: incorrect account sequence")
+	if !isSequenceMismatch(err) {
+		t.Fatalf("expected mismatch: %v", err)
+	}
+	if isSequenceMismatch(fmt.Errorf("insufficient fee")) {
+		t.Fatal("unexpected mismatch")
+	}
+	if !isSequenceMismatchCode("sdk", 32) || isSequenceMismatchCode("wasm", 32) {
+		t.Fatal("sequence mismatch code detection")
+	}
+}
+
+func TestSignAndBroadcastResyncs(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name string
+		opts []TxOption
+	}{
+		{name: "checktx mismatch", opts: fixedTxOptions("checktx")},
+		// Without a gas limit the simulation is what catches the mismatch.
+		{name: "simulate mismatch", opts: []TxOption{WithFixedFee(sdk.NewCoins(sdk.NewInt64Coin("nhash", 1)))}},
+	} {
+		n, c := startChainNode(t, 5)
+		// Two txs from elsewhere wait in the mempool, so the committed sequence is behind too.
+		n.mempool = []chainTx{{hash: "A", seq: 5}, {hash: "B", seq: 6}}
+
+		resp, err := c.SignAndBroadcast(context.Background(), sendMsgs(c), tc.opts...)
+		if err != nil || resp.TxResponse.Code != 0 {
+			t.Fatalf("%s: %v, %v", tc.name, resp, err)
+		}
+		if !slices.Equal(n.accepted, []uint64{7}) || n.mismatches != 1 || c.Sequence != 8 {
+			t.Fatalf("%s: accepted %v after %d mismatches, sequence %d", tc.name, n.accepted, n.mismatches, c.Sequence)
+		}
+	}
+}
+
+func TestSignAndBroadcastGivesUp(t *testing.T) {
+	t.Parallel()
+	n, c := startChainNode(t, 5)
+	// Every attempt loses its sequence to another client.
+	n.racers = 10
+
+	resp, err := c.SignAndBroadcast(context.Background(), sendMsgs(c), fixedTxOptions("lost")...)
+	if err != nil || !isSequenceMismatchCode(resp.TxResponse.Codespace, resp.TxResponse.Code) {
+		t.Fatalf("got %v, %v; want the last sequence mismatch", resp, err)
+	}
+	if n.mismatches != c.BroadcastRetryPolicy.MaxAttempts || len(n.accepted) != 0 {
+		t.Fatalf("%d mismatches, %d accepted; want %d attempts", n.mismatches, len(n.accepted), c.BroadcastRetryPolicy.MaxAttempts)
+	}
+}
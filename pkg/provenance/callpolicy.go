@@ -0,0 +1,175 @@
+package provenance
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CallPolicy is applied to every gRPC call a client makes: a default deadline, retries with jittered
+// backoff and a rate limit. Zero fields fall back to DefaultCallPolicy.
+type CallPolicy struct {
+	// Timeout is the deadline given to calls whose context has none, covering all retries. Negative
+	// leaves such calls unbounded.
+	Timeout time.Duration
+
+	// Retry bounds the attempts of calls failing with one of RetryCodes. Each wait is drawn at random
+	// up to Retry.Backoff(attempt). MaxAttempts of 1 disables retries.
+	Retry RetryPolicy
+
+	// RetryCodes are the status codes worth retrying.
+	RetryCodes []codes.Code
+
+	// NoRetryMethods are full gRPC method names that are never retried, since repeating them is not safe.
+	NoRetryMethods []string
+
+	// RateLimit is the most calls per second the client starts, across all endpoints. 0 is unlimited.
+	RateLimit float64
+
+	// Burst is how many calls may start at once before RateLimit applies. It defaults to RateLimit,
+	// rounded up.
+	Burst int
+}
+
+// DefaultCallPolicy gives calls 30s, retries UNAVAILABLE and RESOURCE_EXHAUSTED up to three times and
+// does not rate limit. Broadcasts are not retried here; SignAndBroadcast has its own retries.
+var DefaultCallPolicy = CallPolicy{
+	Timeout: 30 * time.Second,
+	Retry: RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+	},
+	RetryCodes:     []codes.Code{codes.Unavailable, codes.ResourceExhausted},
+	NoRetryMethods: []string{"/cosmos.tx.v1beta1.Service/BroadcastTx"},
+}
+
+// WithCallPolicy replaces DefaultCallPolicy for the client's calls.
+func WithCallPolicy(p CallPolicy) ClientOption {
+	return func(c *ProvenanceClient) {
+		c.CallPolicy = p
+	}
+}
+
+func (p CallPolicy) withDefaults() CallPolicy {
+	if p.Timeout == 0 {
+		p.Timeout = DefaultCallPolicy.Timeout
+	}
+	if p.Retry.MaxAttempts == 0 {
+		p.Retry = DefaultCallPolicy.Retry
+	}
+	if p.RetryCodes == nil {
+		p.RetryCodes = DefaultCallPolicy.RetryCodes
+	}
+	if p.NoRetryMethods == nil {
+		p.NoRetryMethods = DefaultCallPolicy.NoRetryMethods
+	}
+	if p.RateLimit > 0 && p.Burst <= 0 {
+		p.Burst = int(math.Ceil(p.RateLimit))
+	}
+	return p
+}
+
+// Interceptors returns the unary and stream interceptors enforcing the policy. They share one rate
+// limiter, so each call to Interceptors starts a separate budget. Streams are rate limited but neither
+// given a deadline nor retried.
+func (p CallPolicy) Interceptors() (grpc.UnaryClientInterceptor, grpc.StreamClientInterceptor) {
+	p = p.withDefaults()
+
+	var limiter *tokenBucket
+	if p.RateLimit > 0 {
+		limiter = newTokenBucket(p.RateLimit, p.Burst)
+	}
+
+	unary := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok && p.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+			defer cancel()
+		}
+
+		retry := !slices.Contains(p.NoRetryMethods, method)
+		for attempt := 1; ; attempt++ {
+			if limiter != nil {
+				if err := limiter.wait(ctx); err != nil {
+					return status.FromContextError(err).Err()
+				}
+			}
+
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || !retry || attempt >= p.Retry.MaxAttempts || !slices.Contains(p.RetryCodes, status.Code(err)) {
+				return err
+			}
+
+			// Full jitter keeps clients that failed together from retrying together.
+			backoff := time.Duration(rand.Int64N(int64(p.Retry.Backoff(attempt)) + 1))
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(backoff):
+			}
+		}
+	}
+
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if limiter != nil {
+			if err := limiter.wait(ctx); err != nil {
+				return nil, status.FromContextError(err).Err()
+			}
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+
+	return unary, stream
+}
+
+// tokenBucket allows rate events per second on average and up to burst at once.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait takes a token, blocking until one is available or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	// Take the token now, going into debt if needed, so waiters are served in the order they arrived.
+	b.tokens--
+	if b.tokens >= 0 {
+		b.mu.Unlock()
+		return nil
+	}
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Hand the token back for the next caller.
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,136 @@
+package provenance
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tmtypes "cosmossdk.io/api/cosmos/base/tendermint/v1beta1"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flakyNode fails its first failures calls with code, and hangs for hang on every call.
+type flakyNode struct {
+	tmtypes.UnimplementedServiceServer
+
+	code     codes.Code
+	failures atomic.Int64
+	hang     time.Duration
+	calls    atomic.Int64
+}
+
+func (n *flakyNode) serve(t *testing.T) string {
+	t.Helper()
+	srv := grpc.NewServer()
+	tmtypes.RegisterServiceServer(srv, n)
+	txtypes.RegisterServiceServer(srv, flakyTxService{node: n})
+	return serveTestServer(t, srv)
+}
+
+func dialWithPolicy(t *testing.T, addr string, p CallPolicy) *GRPCConnection {
+	t.Helper()
+	unary, stream := p.Interceptors()
+	conn, err := DialEndpoints([]Endpoint{{URI: addr}}, ConnectionOptions{
+		UnaryInterceptors:  []grpc.UnaryClientInterceptor{unary},
+		StreamInterceptors: []grpc.StreamClientInterceptor{stream},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func (n *flakyNode) result() error {
+	n.calls.Add(1)
+	time.Sleep(n.hang)
+	if n.failures.Add(-1) >= 0 {
+		return status.Error(n.code, "try again")
+	}
+	return nil
+}
+
+func (n *flakyNode) GetNodeInfo(context.Context, *tmtypes.GetNodeInfoRequest) (*tmtypes.GetNodeInfoResponse, error) {
+	return &tmtypes.GetNodeInfoResponse{}, n.result()
+}
+
+type flakyTxService struct {
+	*txtypes.UnimplementedServiceServer
+	node *flakyNode
+}
+
+func (s flakyTxService) BroadcastTx(context.Context, *txtypes.BroadcastTxRequest) (*txtypes.BroadcastTxResponse, error) {
+	return &txtypes.BroadcastTxResponse{}, s.node.result()
+}
+
+func TestCallPolicyRetries(t *testing.T) {
+	t.Parallel()
+	for _, code := range []codes.Code{codes.Unavailable, codes.ResourceExhausted} {
+		n := &flakyNode{code: code}
+		n.failures.Store(2)
+		conn := dialWithPolicy(t, n.serve(t), DefaultCallPolicy)
+
+		if _, err := tmtypes.NewServiceClient(conn).GetNodeInfo(context.Background(), &tmtypes.GetNodeInfoRequest{}); err != nil {
+			t.Fatalf("%s: expected retries to succeed, got %v", code, err)
+		}
+		if got := n.calls.Load(); got != 3 {
+			t.Fatalf("%s: node saw %d calls, want 3", code, got)
+		}
+	}
+
+	// Other codes, and broadcasts, fail on the first attempt.
+	n := &flakyNode{code: codes.InvalidArgument}
+	n.failures.Store(1)
+	conn := dialWithPolicy(t, n.serve(t), DefaultCallPolicy)
+	if _, err := tmtypes.NewServiceClient(conn).GetNodeInfo(context.Background(), &tmtypes.GetNodeInfoRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	n = &flakyNode{code: codes.Unavailable}
+	n.failures.Store(1)
+	conn = dialWithPolicy(t, n.serve(t), DefaultCallPolicy)
+	if _, err := txtypes.NewServiceClient(conn).BroadcastTx(context.Background(), &txtypes.BroadcastTxRequest{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected broadcast not to be retried, got %v", err)
+	}
+	if got := n.calls.Load(); got != 1 {
+		t.Fatalf("node saw %d broadcasts, want 1", got)
+	}
+}
+
+func TestCallPolicyTimeoutAndRateLimit(t *testing.T) {
+	t.Parallel()
+	slow := &flakyNode{hang: 200 * time.Millisecond}
+	conn := dialWithPolicy(t, slow.serve(t), CallPolicy{Timeout: 50 * time.Millisecond})
+	if _, err := tmtypes.NewServiceClient(conn).GetNodeInfo(context.Background(), &tmtypes.GetNodeInfoRequest{}); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+
+	n := &flakyNode{}
+	conn = dialWithPolicy(t, n.serve(t), CallPolicy{RateLimit: 20, Burst: 1})
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := tmtypes.NewServiceClient(conn).GetNodeInfo(context.Background(), &tmtypes.GetNodeInfoRequest{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first call uses the burst; the other four wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Fatalf("5 calls at 20/s took %s", elapsed)
+	}
+}
+
+func TestTokenBucketCancel(t *testing.T) {
+	t.Parallel()
+	b := newTokenBucket(1, 1)
+	if err := b.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.wait(ctx); err == nil {
+		t.Fatal("expected wait to give up with the context")
+	}
+}
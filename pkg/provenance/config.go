@@ -1,6 +1,25 @@
 package provenance
 
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Network identifies which chain a BlockchainConfig targets.
+type Network string
+
+const (
+	NetworkMainnet  Network = "mainnet"
+	NetworkTestnet  Network = "testnet"
+	NetworkLocalnet Network = "localnet"
+
+	// NetworkCustom is any other chain. Every config field must be given explicitly.
+	NetworkCustom Network = "custom"
+)
+
 type BlockchainConfig struct {
+	network       Network
 	uri           string
 	tls           bool
 	addressPrefix string
@@ -9,6 +28,11 @@ type BlockchainConfig struct {
 	chainID       string
 	gasPrice      int64
 	denom         string
+
+	// Additional nodes to spread calls across, see DialEndpoints.
+	endpoints []Endpoint
+
+	grpcOptions GRPCOptions
 }
 
 type BlockchainConfigProvider interface {
@@ -29,11 +53,64 @@ type BlockchainConfigProvider interface {
 	Secure(s bool)
 }
 
+// NetworkProvider is an optional extension of BlockchainConfigProvider for configs that know which
+// network they target. See ConfigNetwork.
+type NetworkProvider interface {
+	Network() Network
+}
+
+// EndpointsProvider is an optional extension of BlockchainConfigProvider for configs that spread calls
+// across several nodes. See ConfigEndpoints.
+type EndpointsProvider interface {
+	// Endpoints returns every node to connect to, starting with URI().
+	Endpoints() []Endpoint
+}
+
+// GRPCOptionsProvider is an optional extension of BlockchainConfigProvider for configs that customize
+// how the nodes are dialed. See ConfigGRPCOptions.
+type GRPCOptionsProvider interface {
+	GRPCOptions() GRPCOptions
+}
+
 // Verify that the BlockchainConfig implement the BlockchainConfigProvider interface
 var _ BlockchainConfigProvider = (*BlockchainConfig)(nil)
+var _ NetworkProvider = (*BlockchainConfig)(nil)
+var _ EndpointsProvider = (*BlockchainConfig)(nil)
+var _ GRPCOptionsProvider = (*BlockchainConfig)(nil)
+
+// ConfigNetwork returns the network conf targets. Providers that don't implement NetworkProvider are
+// reported as mainnet or custom according to Mainnet().
+func ConfigNetwork(conf BlockchainConfigProvider) Network {
+	if n, ok := conf.(NetworkProvider); ok {
+		return n.Network()
+	}
+	if conf.Mainnet() {
+		return NetworkMainnet
+	}
+	return NetworkCustom
+}
+
+// ConfigEndpoints returns every node conf connects to. Providers that don't implement EndpointsProvider
+// have the single node given by URI() and TLS().
+func ConfigEndpoints(conf BlockchainConfigProvider) []Endpoint {
+	if e, ok := conf.(EndpointsProvider); ok {
+		return e.Endpoints()
+	}
+	return []Endpoint{{URI: conf.URI(), TLS: conf.TLS()}}
+}
+
+// ConfigGRPCOptions returns how conf's nodes are dialed. Providers that don't implement
+// GRPCOptionsProvider get the zero GRPCOptions.
+func ConfigGRPCOptions(conf BlockchainConfigProvider) GRPCOptions {
+	if o, ok := conf.(GRPCOptionsProvider); ok {
+		return o.GRPCOptions()
+	}
+	return GRPCOptions{}
+}
 
 func NewMainnetConfig() *BlockchainConfig {
 	return &BlockchainConfig{
+		network:       NetworkMainnet,
 		uri:           "grpc.provenance.io:443",
 		tls:           true,
 		addressPrefix: "pb",
@@ -47,6 +124,7 @@ func NewMainnetConfig() *BlockchainConfig {
 
 func NewTestnetConfig() *BlockchainConfig {
 	return &BlockchainConfig{
+		network:       NetworkTestnet,
 		uri:           "grpc.test.provenance.io:443",
 		tls:           true,
 		addressPrefix: "tp",
@@ -58,6 +136,39 @@ func NewTestnetConfig() *BlockchainConfig {
 	}
 }
 
+// NewLocalnetConfig targets a provenanced node started locally with --testnet, e.g. via `make localnet-start`
+// or `make run` in the provenance repo.
+func NewLocalnetConfig() *BlockchainConfig {
+	return &BlockchainConfig{
+		network:       NetworkLocalnet,
+		uri:           "localhost:9090",
+		tls:           false,
+		addressPrefix: "tp",
+		publicPrefix:  "tppub",
+		coinType:      1,
+		chainID:       "testing",
+		gasPrice:      1,
+		denom:         "nhash",
+	}
+}
+
+// NewNetworkConfig returns the profile for n. NetworkCustom starts out empty and must be filled in
+// before use, see ConfigSpec.
+func NewNetworkConfig(n Network) (*BlockchainConfig, error) {
+	switch n {
+	case NetworkMainnet:
+		return NewMainnetConfig(), nil
+	case NetworkTestnet:
+		return NewTestnetConfig(), nil
+	case NetworkLocalnet:
+		return NewLocalnetConfig(), nil
+	case NetworkCustom:
+		return &BlockchainConfig{network: NetworkCustom}, nil
+	default:
+		return nil, fmt.Errorf("unknown network %q: must be one of mainnet, testnet, localnet or custom", n)
+	}
+}
+
 func (c *BlockchainConfig) URI() string {
 	return c.uri
 }
@@ -98,6 +209,64 @@ func (c *BlockchainConfig) Secure(s bool) {
 	c.tls = s
 }
 
+// AddEndpoint adds another node for the client to spread calls across and fail over to.
+func (c *BlockchainConfig) AddEndpoint(uri string, tls bool) {
+	c.endpoints = append(c.endpoints, Endpoint{URI: uri, TLS: tls})
+}
+
+func (c *BlockchainConfig) Endpoints() []Endpoint {
+	return append([]Endpoint{{URI: c.uri, TLS: c.tls}}, c.endpoints...)
+}
+
+func (c *BlockchainConfig) GRPCOptions() GRPCOptions {
+	return c.grpcOptions
+}
+
+// SetGRPCOptions replaces the options used to dial the nodes.
+func (c *BlockchainConfig) SetGRPCOptions(o GRPCOptions) {
+	c.grpcOptions = o
+}
+
 func (c *BlockchainConfig) Mainnet() bool {
-	return true
+	return c.network == NetworkMainnet
+}
+
+func (c *BlockchainConfig) Network() Network {
+	return c.network
+}
+
+// Validate reports the first missing or malformed setting.
+func (c *BlockchainConfig) Validate() error {
+	switch c.network {
+	case NetworkMainnet, NetworkTestnet, NetworkLocalnet, NetworkCustom:
+	default:
+		return fmt.Errorf("invalid config: unknown network %q", c.network)
+	}
+	if c.uri == "" {
+		return fmt.Errorf("invalid config: uri is required")
+	}
+	for _, ep := range c.endpoints {
+		if ep.URI == "" {
+			return fmt.Errorf("invalid config: endpoint uri is required")
+		}
+	}
+	if c.chainID == "" {
+		return fmt.Errorf("invalid config: chain id is required")
+	}
+	if c.addressPrefix == "" || c.publicPrefix == "" {
+		return fmt.Errorf("invalid config: address and public key prefixes are required")
+	}
+	if err := sdk.ValidateDenom(c.denom); err != nil {
+		return fmt.Errorf("invalid config: denom: %w", err)
+	}
+	if c.gasPrice < 0 {
+		return fmt.Errorf("invalid config: gas price must not be negative, got %d", c.gasPrice)
+	}
+	if o := c.grpcOptions; (o.CertFile == "") != (o.KeyFile == "") {
+		return fmt.Errorf("invalid config: grpc client certificate and key must be provided together")
+	}
+	if c.grpcOptions.MaxRecvMsgSize < 0 {
+		return fmt.Errorf("invalid config: grpc max receive message size must not be negative")
+	}
+	return nil
 }
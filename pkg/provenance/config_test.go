@@ -0,0 +1,152 @@
+package provenance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNetworkProfiles(t *testing.T) {
+	t.Parallel()
+	if !NewMainnetConfig().Mainnet() {
+		t.Fatal("mainnet config is not mainnet")
+	}
+	for _, c := range []*BlockchainConfig{NewTestnetConfig(), NewLocalnetConfig()} {
+		if c.Mainnet() {
+			t.Fatalf("%s config reports mainnet", c.Network())
+		}
+		if err := c.Validate(); err != nil {
+			t.Fatalf("%s: %v", c.Network(), err)
+		}
+	}
+}
+
+// baselineConfig implements only the required BlockchainConfigProvider methods, like a provider written
+// outside this package would.
+type baselineConfig struct {
+	uri     string
+	tls     bool
+	mainnet bool
+}
+
+func (c *baselineConfig) URI() string           { return c.uri }
+func (c *baselineConfig) TLS() bool             { return c.tls }
+func (c *baselineConfig) AddressPrefix() string { return "tp" }
+func (c *baselineConfig) PublicPrefix() string  { return "tppub" }
+func (c *baselineConfig) CoinType() uint32      { return 1 }
+func (c *baselineConfig) ChainID() string       { return "testing" }
+func (c *baselineConfig) GasPrice() int64       { return 1 }
+func (c *baselineConfig) Denom() string         { return "nhash" }
+func (c *baselineConfig) Mainnet() bool         { return c.mainnet }
+func (c *baselineConfig) NodeUrl(url string)    { c.uri = url }
+func (c *baselineConfig) Secure(s bool)         { c.tls = s }
+
+func TestConfigNetwork(t *testing.T) {
+	t.Parallel()
+	if got := ConfigNetwork(NewLocalnetConfig()); got != NetworkLocalnet {
+		t.Fatalf("got %s want %s", got, NetworkLocalnet)
+	}
+	if got := ConfigNetwork(&baselineConfig{mainnet: true}); got != NetworkMainnet {
+		t.Fatalf("got %s want %s", got, NetworkMainnet)
+	}
+	if got := ConfigNetwork(&baselineConfig{}); got != NetworkCustom {
+		t.Fatalf("got %s want %s", got, NetworkCustom)
+	}
+}
+
+func TestConfigEndpoints(t *testing.T) {
+	t.Parallel()
+	c := NewLocalnetConfig()
+	c.AddEndpoint("node2:9090", true)
+	if got := ConfigEndpoints(c); len(got) != 2 || got[0].URI != "localhost:9090" || got[1] != (Endpoint{URI: "node2:9090", TLS: true}) {
+		t.Fatalf("unexpected endpoints %+v", got)
+	}
+	got := ConfigEndpoints(&baselineConfig{uri: "node:443", tls: true})
+	if len(got) != 1 || got[0] != (Endpoint{URI: "node:443", TLS: true}) {
+		t.Fatalf("unexpected endpoints %+v", got)
+	}
+}
+
+func TestConfigGRPCOptions(t *testing.T) {
+	t.Parallel()
+	c := NewLocalnetConfig()
+	c.SetGRPCOptions(GRPCOptions{BearerToken: "s3cret"})
+	if got := ConfigGRPCOptions(c); got.BearerToken != "s3cret" {
+		t.Fatalf("unexpected grpc options %+v", got)
+	}
+	if got := ConfigGRPCOptions(&baselineConfig{}); got.BearerToken != "" || got.Headers != nil || got.MaxRecvMsgSize != 0 {
+		t.Fatalf("unexpected grpc options %+v", got)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{
+		"prov.toml": "network = \"localnet\"\nuri = \"node:9090\"\ngas_price = 19050\n",
+		"prov.yaml": "network: localnet\nuri: node:9090\ngas_price: 19050\n",
+		"prov.json": `{"network": "localnet", "uri": "node:9090", "gas_price": 19050}`,
+	}
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		c, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if c.URI() != "node:9090" || c.GasPrice() != 19050 || c.ChainID() != "testing" || c.Network() != NetworkLocalnet {
+			t.Fatalf("%s: unexpected config %+v", name, c)
+		}
+	}
+
+	path := filepath.Join(dir, "typo.toml")
+	if err := os.WriteFile(path, []byte("network = \"localnet\"\nchainid = \"x\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Parallel()
+	env := map[string]string{
+		"PROV_URI":            "grpc.example.com:443",
+		"PROV_TLS":            "true",
+		"PROV_ADDRESS_PREFIX": "tp",
+		"PROV_PUBLIC_PREFIX":  "tppub",
+		"PROV_COIN_TYPE":      "1",
+		"PROV_CHAIN_ID":       "custom-1",
+		"PROV_GAS_PRICE":      "2",
+		"PROV_DENOM":          "nhash",
+	}
+	lookup := func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}
+
+	spec, err := specFromEnv(lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := spec.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Network() != NetworkCustom || !c.TLS() || c.CoinType() != 1 || c.ChainID() != "custom-1" {
+		t.Fatalf("unexpected config %+v", c)
+	}
+
+	delete(env, "PROV_CHAIN_ID")
+	spec, _ = specFromEnv(lookup)
+	if _, err := spec.Build(); err == nil {
+		t.Fatal("expected custom config without chain id to fail validation")
+	}
+
+	env["PROV_TLS"] = "maybe"
+	if _, err := specFromEnv(lookup); err == nil {
+		t.Fatal("expected error for invalid PROV_TLS")
+	}
+}
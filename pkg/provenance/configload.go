@@ -0,0 +1,263 @@
+package provenance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigSpec is the file and environment form of a BlockchainConfig. The config starts from the Network
+// profile (NetworkCustom when empty) and every field that is set overrides the profile's value.
+type ConfigSpec struct {
+	Network       Network `json:"network" yaml:"network" toml:"network"`
+	URI           string  `json:"uri" yaml:"uri" toml:"uri"`
+	TLS           *bool   `json:"tls" yaml:"tls" toml:"tls"`
+	AddressPrefix string  `json:"address_prefix" yaml:"address_prefix" toml:"address_prefix"`
+	PublicPrefix  string  `json:"public_prefix" yaml:"public_prefix" toml:"public_prefix"`
+	CoinType      *uint32 `json:"coin_type" yaml:"coin_type" toml:"coin_type"`
+	ChainID       string  `json:"chain_id" yaml:"chain_id" toml:"chain_id"`
+	GasPrice      *int64  `json:"gas_price" yaml:"gas_price" toml:"gas_price"`
+	Denom         string  `json:"denom" yaml:"denom" toml:"denom"`
+
+	// Endpoints are additional nodes to spread calls across and fail over to.
+	Endpoints []Endpoint `json:"endpoints" yaml:"endpoints" toml:"endpoints"`
+
+	// GRPC customizes how the nodes are dialed.
+	GRPC *GRPCSpec `json:"grpc" yaml:"grpc" toml:"grpc"`
+}
+
+// GRPCSpec is the file and environment form of GRPCOptions. Durations use time.ParseDuration syntax.
+type GRPCSpec struct {
+	CAFile           string            `json:"ca_file" yaml:"ca_file" toml:"ca_file"`
+	CertFile         string            `json:"cert_file" yaml:"cert_file" toml:"cert_file"`
+	KeyFile          string            `json:"key_file" yaml:"key_file" toml:"key_file"`
+	Headers          map[string]string `json:"headers" yaml:"headers" toml:"headers"`
+	BearerToken      string            `json:"bearer_token" yaml:"bearer_token" toml:"bearer_token"`
+	KeepaliveTime    string            `json:"keepalive_time" yaml:"keepalive_time" toml:"keepalive_time"`
+	KeepaliveTimeout string            `json:"keepalive_timeout" yaml:"keepalive_timeout" toml:"keepalive_timeout"`
+	MaxRecvMsgSize   int               `json:"max_recv_msg_size" yaml:"max_recv_msg_size" toml:"max_recv_msg_size"`
+}
+
+func (s GRPCSpec) options() (GRPCOptions, error) {
+	o := GRPCOptions{
+		CAFile:         s.CAFile,
+		CertFile:       s.CertFile,
+		KeyFile:        s.KeyFile,
+		Headers:        s.Headers,
+		BearerToken:    s.BearerToken,
+		MaxRecvMsgSize: s.MaxRecvMsgSize,
+	}
+	var err error
+	if s.KeepaliveTime != "" {
+		if o.KeepaliveTime, err = time.ParseDuration(s.KeepaliveTime); err != nil {
+			return o, fmt.Errorf("invalid grpc keepalive_time %q: %w", s.KeepaliveTime, err)
+		}
+	}
+	if s.KeepaliveTimeout != "" {
+		if o.KeepaliveTimeout, err = time.ParseDuration(s.KeepaliveTimeout); err != nil {
+			return o, fmt.Errorf("invalid grpc keepalive_timeout %q: %w", s.KeepaliveTimeout, err)
+		}
+	}
+	return o, nil
+}
+
+// Build applies the spec on top of its network profile and validates the result.
+func (s ConfigSpec) Build() (*BlockchainConfig, error) {
+	network := s.Network
+	if network == "" {
+		network = NetworkCustom
+	}
+	c, err := NewNetworkConfig(network)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.URI != "" {
+		c.uri = s.URI
+	}
+	if s.TLS != nil {
+		c.tls = *s.TLS
+	}
+	if s.AddressPrefix != "" {
+		c.addressPrefix = s.AddressPrefix
+	}
+	if s.PublicPrefix != "" {
+		c.publicPrefix = s.PublicPrefix
+	}
+	if s.CoinType != nil {
+		c.coinType = *s.CoinType
+	}
+	if s.ChainID != "" {
+		c.chainID = s.ChainID
+	}
+	if s.GasPrice != nil {
+		c.gasPrice = *s.GasPrice
+	}
+	if s.Denom != "" {
+		c.denom = s.Denom
+	}
+	for _, ep := range s.Endpoints {
+		c.AddEndpoint(ep.URI, ep.TLS)
+	}
+	if s.GRPC != nil {
+		o, err := s.GRPC.options()
+		if err != nil {
+			return nil, err
+		}
+		c.SetGRPCOptions(o)
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadConfig reads a ConfigSpec from path and builds it. The format is chosen by extension: .toml,
+// .yaml/.yml or .json. Unknown keys are rejected so that typos don't silently fall back to the profile.
+//
+// Example (TOML):
+//
+//	network = "localnet"
+//	uri = "localhost:9090"
+//	chain_id = "testing"
+func LoadConfig(path string) (*BlockchainConfig, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config: %w", err)
+	}
+
+	var spec ConfigSpec
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".toml":
+		dec := toml.NewDecoder(bytes.NewReader(bz))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&spec)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(bz))
+		dec.KnownFields(true)
+		err = dec.Decode(&spec)
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(bz))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&spec)
+	default:
+		return nil, fmt.Errorf("unsupported config format %q: use .toml, .yaml, .yml or .json", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config %s: %w", path, err)
+	}
+
+	return spec.Build()
+}
+
+// ConfigFromEnv builds a config from PROV_* environment variables:
+//
+//	PROV_NETWORK         mainnet, testnet, localnet or custom
+//	PROV_URI             gRPC endpoint, host:port
+//	PROV_TLS             true or false
+//	PROV_ADDRESS_PREFIX  bech32 account prefix, e.g. pb
+//	PROV_PUBLIC_PREFIX   bech32 public key prefix, e.g. pbpub
+//	PROV_COIN_TYPE       BIP44 coin type
+//	PROV_CHAIN_ID        chain id
+//	PROV_GAS_PRICE       gas price in Denom
+//	PROV_DENOM           fee denom
+//	PROV_ENDPOINTS       comma-separated additional gRPC endpoints, using PROV_TLS
+//	PROV_GRPC_CA_FILE    PEM CA bundle trusted for TLS endpoints
+//	PROV_GRPC_CERT_FILE  PEM client certificate for mTLS
+//	PROV_GRPC_KEY_FILE   PEM client key for mTLS
+//	PROV_GRPC_TOKEN      bearer token sent with every call
+//	PROV_GRPC_KEEPALIVE  keepalive ping interval, e.g. 30s
+//	PROV_GRPC_MAX_RECV   largest response accepted, in bytes
+//
+// Unset variables keep the value from the PROV_NETWORK profile.
+func ConfigFromEnv() (*BlockchainConfig, error) {
+	spec, err := specFromEnv(os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	return spec.Build()
+}
+
+func specFromEnv(lookup func(string) (string, bool)) (ConfigSpec, error) {
+	get := func(key string) string {
+		v, _ := lookup(key)
+		return strings.TrimSpace(v)
+	}
+
+	spec := ConfigSpec{
+		Network:       Network(strings.ToLower(get("PROV_NETWORK"))),
+		URI:           get("PROV_URI"),
+		AddressPrefix: get("PROV_ADDRESS_PREFIX"),
+		PublicPrefix:  get("PROV_PUBLIC_PREFIX"),
+		ChainID:       get("PROV_CHAIN_ID"),
+		Denom:         get("PROV_DENOM"),
+	}
+
+	if v := get("PROV_TLS"); v != "" {
+		tls, err := strconv.ParseBool(v)
+		if err != nil {
+			return spec, fmt.Errorf("invalid PROV_TLS %q: %w", v, err)
+		}
+		spec.TLS = &tls
+	}
+	if v := get("PROV_COIN_TYPE"); v != "" {
+		coinType, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return spec, fmt.Errorf("invalid PROV_COIN_TYPE %q: %w", v, err)
+		}
+		ct := uint32(coinType)
+		spec.CoinType = &ct
+	}
+	if v := get("PROV_GAS_PRICE"); v != "" {
+		gasPrice, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return spec, fmt.Errorf("invalid PROV_GAS_PRICE %q: %w", v, err)
+		}
+		spec.GasPrice = &gasPrice
+	}
+
+	if v := get("PROV_ENDPOINTS"); v != "" {
+		tls := spec.TLS != nil && *spec.TLS
+		if spec.TLS == nil {
+			// Fall back to the profile's TLS setting.
+			if profile, err := NewNetworkConfig(spec.Network); err == nil {
+				tls = profile.TLS()
+			}
+		}
+		for _, uri := range strings.Split(v, ",") {
+			if uri = strings.TrimSpace(uri); uri != "" {
+				spec.Endpoints = append(spec.Endpoints, Endpoint{URI: uri, TLS: tls})
+			}
+		}
+	}
+
+	grpcSpec := GRPCSpec{
+		CAFile:        get("PROV_GRPC_CA_FILE"),
+		CertFile:      get("PROV_GRPC_CERT_FILE"),
+		KeyFile:       get("PROV_GRPC_KEY_FILE"),
+		BearerToken:   get("PROV_GRPC_TOKEN"),
+		KeepaliveTime: get("PROV_GRPC_KEEPALIVE"),
+	}
+	if v := get("PROV_GRPC_MAX_RECV"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil {
+			return spec, fmt.Errorf("invalid PROV_GRPC_MAX_RECV %q: %w", v, err)
+		}
+		grpcSpec.MaxRecvMsgSize = size
+	}
+	if grpcSpec.CAFile != "" || grpcSpec.CertFile != "" || grpcSpec.KeyFile != "" || grpcSpec.BearerToken != "" ||
+		grpcSpec.KeepaliveTime != "" || grpcSpec.MaxRecvMsgSize != 0 {
+		spec.GRPC = &grpcSpec
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,40 @@
+package provenance
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestContextVariantsHonorCancellation(t *testing.T) {
+	t.Parallel()
+	c := unreachableClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := map[string]func() error{
+		"GetScopeContext": func() error {
+			_, err := c.GetScopeContext(ctx, "scope1qzge0zaztu65tx5x5llv5xc9ztsqxlkwel")
+			return err
+		},
+		"GetMarkerContext": func() error {
+			_, err := c.GetMarkerContext(ctx, "nhash")
+			return err
+		},
+		"GetAccountInfoContext": func() error {
+			_, _, err := c.GetAccountInfoContext(ctx, "tp1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqxp3ca")
+			return err
+		},
+		"BroadcastTxContext": func() error {
+			_, err := c.BroadcastTxContext(ctx, testTxBytes(t))
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); status.Code(err) != codes.Canceled {
+			t.Fatalf("%s: expected Canceled, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"sync"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	marker "github.com/provenance-io/provenance/x/marker/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dcshock/prov-go/pkg/cw20"
+)
+
+// DenomUnit is a unit a base denom is counted in: one of it is 10^Exponent base units.
+type DenomUnit struct {
+	Denom    string
+	Exponent uint32
+}
+
+// DenomInfo describes the units of a base denom.
+type DenomInfo struct {
+	// Base is the denom amounts are held in on chain, e.g. "nhash".
+	Base string
+	// Display is the unit amounts are shown in, e.g. "hash". It must be one of Units.
+	Display string
+	// Units lists every unit, including Base with exponent 0.
+	Units []DenomUnit
+}
+
+func (d DenomInfo) exponent(unit string) (uint32, bool) {
+	for _, u := range d.Units {
+		if u.Denom == unit {
+			return u.Exponent, true
+		}
+	}
+	return 0, false
+}
+
+// HashDenom is nhash and hash, the units of the chain's fee token.
+var HashDenom = DenomInfo{
+	Base:    "nhash",
+	Display: "hash",
+	Units:   []DenomUnit{{Denom: "nhash", Exponent: 0}, {Denom: "hash", Exponent: 9}},
+}
+
+// DenomRegistry converts amounts between the base and display units of the denoms registered with
+// it. Denoms it does not know are treated as having only their base unit.
+type DenomRegistry struct {
+	mu     sync.RWMutex
+	denoms map[string]DenomInfo
+	// units maps every unit of every denom to its base denom.
+	units map[string]string
+}
+
+// NewDenomRegistry returns a registry that knows HashDenom.
+func NewDenomRegistry() *DenomRegistry {
+	r := &DenomRegistry{
+		denoms: map[string]DenomInfo{},
+		units:  map[string]string{},
+	}
+	if err := r.Register(HashDenom); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Register adds info, replacing what is registered for the same base denom. A unit already used by
+// another base denom is an error wrapping ErrAlreadyExists.
+func (r *DenomRegistry) Register(info DenomInfo) error {
+	if info.Base == "" {
+		return fmt.Errorf("denom info has no base denom")
+	}
+	if _, ok := info.exponent(info.Base); !ok {
+		info.Units = append([]DenomUnit{{Denom: info.Base}}, info.Units...)
+	}
+	if info.Display == "" {
+		info.Display = info.Base
+	}
+	if _, ok := info.exponent(info.Display); !ok {
+		return fmt.Errorf("display unit %q is not a unit of %s", info.Display, info.Base)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range info.Units {
+		if base, ok := r.units[u.Denom]; ok && base != info.Base {
+			return fmt.Errorf("unit %q of %s is a unit of %s: %w", u.Denom, info.Base, base, ErrAlreadyExists)
+		}
+	}
+	if old, ok := r.denoms[info.Base]; ok {
+		for _, u := range old.Units {
+			delete(r.units, u.Denom)
+		}
+	}
+	for _, u := range info.Units {
+		r.units[u.Denom] = info.Base
+	}
+	r.denoms[info.Base] = info
+	return nil
+}
+
+// RegisterMetadata registers the units of bank denom metadata, including their aliases. Metadata
+// without a display unit is displayed in its largest unit.
+func (r *DenomRegistry) RegisterMetadata(md banktypes.Metadata) error {
+	info := DenomInfo{Base: md.Base, Display: md.Display}
+	var largest DenomUnit
+	for _, u := range md.DenomUnits {
+		info.Units = append(info.Units, DenomUnit{Denom: u.Denom, Exponent: u.Exponent})
+		for _, alias := range u.Aliases {
+			info.Units = append(info.Units, DenomUnit{Denom: alias, Exponent: u.Exponent})
+		}
+		if u.Exponent >= largest.Exponent {
+			largest = DenomUnit{Denom: u.Denom, Exponent: u.Exponent}
+		}
+	}
+	if info.Display == "" {
+		info.Display = largest.Denom
+	}
+	return r.Register(info)
+}
+
+// Lookup returns the info of the denom that unit is a unit of.
+func (r *DenomRegistry) Lookup(unit string) (DenomInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	base, ok := r.units[unit]
+	if !ok {
+		return DenomInfo{}, false
+	}
+	return r.denoms[base], true
+}
+
+var decCoinRegex = regexp.MustCompile(`^\s*([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z][a-zA-Z0-9/:._-]*)\s*$`)
+
+// ParseCoin parses an amount in any registered unit, such as "1.5hash" or "1500000000nhash", into
+// base units. Amounts of unregistered denoms must be whole.
+func (r *DenomRegistry) ParseCoin(s string) (sdk.Coin, error) {
+	m := decCoinRegex.FindStringSubmatch(s)
+	if m == nil {
+		return sdk.Coin{}, fmt.Errorf("invalid coin %q", s)
+	}
+	amount, unit := m[1], m[2]
+
+	base, exp := unit, uint32(0)
+	if info, ok := r.Lookup(unit); ok {
+		base = info.Base
+		exp, _ = info.exponent(unit)
+	}
+
+	whole, frac, _ := strings.Cut(amount, ".")
+	frac = strings.TrimRight(frac, "0")
+	if uint32(len(frac)) > exp {
+		return sdk.Coin{}, fmt.Errorf("%s has more than %d decimal places", s, exp)
+	}
+	digits := whole + frac + strings.Repeat("0", int(exp)-len(frac))
+	n, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return sdk.Coin{}, fmt.Errorf("invalid coin %q", s)
+	}
+	return sdk.Coin{Denom: base, Amount: sdkmath.NewIntFromBigInt(n)}, nil
+}
+
+// ParseCoins parses a comma-separated list of coins with ParseCoin.
+func (r *DenomRegistry) ParseCoins(s string) (sdk.Coins, error) {
+	coins := sdk.NewCoins()
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		coin, err := r.ParseCoin(part)
+		if err != nil {
+			return nil, err
+		}
+		coins = coins.Add(coin)
+	}
+	return coins, nil
+}
+
+// FormatAmount formats an amount of base denom in its display unit, with as many decimal places as it
+// needs, e.g. "1.5hash". Amounts of unregistered denoms are formatted as they are.
+func (r *DenomRegistry) FormatAmount(amount sdkmath.Int, denom string) string {
+	if amount.IsNil() {
+		amount = sdkmath.ZeroInt()
+	}
+	info, ok := r.Lookup(denom)
+	if !ok || info.Base != denom {
+		return amount.String() + denom
+	}
+	exp, _ := info.exponent(info.Display)
+
+	sign, digits := "", amount.String()
+	if amount.IsNegative() {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) <= int(exp) {
+		digits = strings.Repeat("0", int(exp)-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-int(exp)], strings.TrimRight(digits[len(digits)-int(exp):], "0")
+	if frac != "" {
+		whole += "." + frac
+	}
+	return sign + whole + info.Display
+}
+
+// FormatCoin formats coin with FormatAmount.
+func (r *DenomRegistry) FormatCoin(coin sdk.Coin) string {
+	return r.FormatAmount(coin.Amount, coin.Denom)
+}
+
+// FormatCoins formats each coin with FormatAmount, separated by commas.
+func (r *DenomRegistry) FormatCoins(coins sdk.Coins) string {
+	parts := make([]string, len(coins))
+	for i, coin := range coins {
+		parts[i] = r.FormatCoin(coin)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Format formats the account's total and the NAV of each of its NFTs with r, one per line.
+func (v AccountValue) Format(r *DenomRegistry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s", v.Address, r.FormatCoin(v.Total))
+	for _, nft := range v.NFTs {
+		nav := "no NAV"
+		if nft.NAV != nil {
+			nav = r.FormatCoin(*nft.NAV)
+		}
+		fmt.Fprintf(&b, "\n  %s: %s", nft.Denom, nav)
+	}
+	return b.String()
+}
+
+// Denoms returns the client's denom registry. It starts out knowing HashDenom; LoadDenoms,
+// LoadDenom and RegisterCW20 add to it.
+func (c *ProvenanceClient) Denoms() *DenomRegistry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.denoms == nil {
+		c.denoms = NewDenomRegistry()
+	}
+	return c.denoms
+}
+
+// LoadDenoms registers the bank metadata of every denom that has any. Metadata that clashes with an
+// already registered denom is skipped and logged.
+func (c *ProvenanceClient) LoadDenoms(ctx context.Context) error {
+	metadatas, err := c.GetAllDenomsMetadata(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting denom metadata: %w", err)
+	}
+
+	r := c.Denoms()
+	for _, md := range metadatas {
+		if err := r.RegisterMetadata(md); err != nil {
+			c.logger().Warn("skipping denom metadata", "denom", md.Base, "error", err)
+		}
+	}
+	return nil
+}
+
+// LoadDenom returns the info of the denom that unit is a unit of, registering the denom's marker
+// metadata first if it is not known yet. A denom without metadata is registered with only its base
+// unit.
+func (c *ProvenanceClient) LoadDenom(ctx context.Context, unit string) (DenomInfo, error) {
+	r := c.Denoms()
+	if info, ok := r.Lookup(unit); ok {
+		return info, nil
+	}
+
+	info := DenomInfo{Base: unit}
+	res, err := (*c.MarkerClient()).DenomMetadata(ctx, &marker.QueryDenomMetadataRequest{Denom: unit})
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return DenomInfo{}, ctx.Err()
+	case err != nil && status.Code(err) != codes.NotFound:
+		return DenomInfo{}, fmt.Errorf("error getting %s metadata: %w", unit, err)
+	case err == nil && res.Metadata.Base != "":
+		if err := r.RegisterMetadata(res.Metadata); err != nil {
+			return DenomInfo{}, err
+		}
+		info, _ = r.Lookup(res.Metadata.Base)
+		return info, nil
+	}
+	if err := r.Register(info); err != nil {
+		return DenomInfo{}, err
+	}
+	return info, nil
+}
+
+// RegisterCW20 registers a CW20 token from its token_info: amounts held in the contract, whose
+// address is the base denom, are displayed in its symbol with its number of decimals.
+func (c *ProvenanceClient) RegisterCW20(ctx context.Context, contractAddress string) (DenomInfo, error) {
+	ti, err := cw20.NewQueryClient(c.Grpc, contractAddress).TokenInfo(ctx)
+	if err != nil {
+		return DenomInfo{}, fmt.Errorf("error getting %s token info: %w", contractAddress, err)
+	}
+
+	info := DenomInfo{
+		Base:    contractAddress,
+		Display: ti.Symbol,
+		Units:   []DenomUnit{{Denom: contractAddress}, {Denom: ti.Symbol, Exponent: uint32(ti.Decimals)}},
+	}
+	if err := c.Denoms().Register(info); err != nil {
+		return DenomInfo{}, err
+	}
+	return info, nil
+}
@@ -0,0 +1,82 @@
+package provenance
+
+import (
+	"errors"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+func TestDenomRegistry(t *testing.T) {
+	t.Parallel()
+	r := NewDenomRegistry()
+	err := r.RegisterMetadata(banktypes.Metadata{
+		Base: "uusdc",
+		DenomUnits: []*banktypes.DenomUnit{
+			{Denom: "uusdc"},
+			{Denom: "usdc", Exponent: 6, Aliases: []string{"USDC"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for in, want := range map[string]sdk.Coin{
+		"1.5hash":         sdk.NewInt64Coin("nhash", 1_500_000_000),
+		"1500000000nhash": sdk.NewInt64Coin("nhash", 1_500_000_000),
+		" 0.000001 USDC":  sdk.NewInt64Coin("uusdc", 1),
+		"2.50usdc":        sdk.NewInt64Coin("uusdc", 2_500_000),
+		"7widget":         sdk.NewInt64Coin("widget", 7),
+	} {
+		got, err := r.ParseCoin(in)
+		if err != nil || !got.IsEqual(want) {
+			t.Fatalf("ParseCoin(%q) = %s, %v; want %s", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"0.0000000001hash", "1.5widget", "hash", "1,5hash"} {
+		if got, err := r.ParseCoin(in); err == nil {
+			t.Fatalf("ParseCoin(%q) = %s, want an error", in, got)
+		}
+	}
+
+	for want, coin := range map[string]sdk.Coin{
+		"1.5hash":         sdk.NewInt64Coin("nhash", 1_500_000_000),
+		"0.000000001hash": sdk.NewInt64Coin("nhash", 1),
+		"0hash":           sdk.NewInt64Coin("nhash", 0),
+		"12usdc":          sdk.NewInt64Coin("uusdc", 12_000_000),
+		"7widget":         sdk.NewInt64Coin("widget", 7),
+	} {
+		if got := r.FormatCoin(coin); got != want {
+			t.Fatalf("FormatCoin(%s) = %q, want %q", coin, got, want)
+		}
+	}
+	if got := r.FormatAmount(sdkmath.NewInt(-2_500_000), "uusdc"); got != "-2.5usdc" {
+		t.Fatalf("negative amount: %q", got)
+	}
+
+	coins, err := r.ParseCoins("1hash,0.5usdc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.FormatCoins(coins); got != "1hash,0.5usdc" {
+		t.Fatalf("FormatCoins = %q", got)
+	}
+
+	nav := sdk.NewInt64Coin("nhash", 500_000_000)
+	value := AccountValue{
+		Address: "tp1owner",
+		Total:   sdk.NewInt64Coin("nhash", 1_500_000_000),
+		NFTs:    []NFTAccount{{Denom: "nft/a", NAV: &nav}, {Denom: "nft/b"}},
+	}
+	if got, want := value.Format(r), "tp1owner: 1.5hash\n  nft/a: 0.5hash\n  nft/b: no NAV"; got != want {
+		t.Fatalf("AccountValue.Format = %q, want %q", got, want)
+	}
+
+	// A unit can only belong to one base denom.
+	err = r.Register(DenomInfo{Base: "cw20addr", Display: "USDC", Units: []DenomUnit{{Denom: "USDC", Exponent: 6}}})
+	if !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("clashing unit: %v", err)
+	}
+}
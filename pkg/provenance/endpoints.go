@@ -0,0 +1,426 @@
+package provenance
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	tmtypes "cosmossdk.io/api/cosmos/base/tendermint/v1beta1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Endpoint is a node's gRPC address.
+type Endpoint struct {
+	URI string `json:"uri" yaml:"uri" toml:"uri"`
+	TLS bool   `json:"tls" yaml:"tls" toml:"tls"`
+}
+
+// DefaultPinnedMethods are the calls that depend on the signer's account sequence as the node sees it.
+// Sending a signer's calls to one node keeps its transactions ordered in a single mempool.
+var DefaultPinnedMethods = []string{
+	"/cosmos.tx.v1beta1.Service/BroadcastTx",
+	"/cosmos.tx.v1beta1.Service/Simulate",
+	"/cosmos.auth.v1beta1.Query/Account",
+}
+
+// PinKey makes a call to one of PinnedMethods go to the endpoint pinned for key, usually the signer's
+// address, so that each signer stays on one node while different signers may be spread across nodes.
+// Calls without a PinKey share one endpoint. The clients of named accounts add their address.
+func PinKey(key string) grpc.CallOption {
+	return pinKeyOption{key: key}
+}
+
+type pinKeyOption struct {
+	grpc.EmptyCallOption
+	key string
+}
+
+// pinKeyOf returns the key of the last PinKey in opts.
+func pinKeyOf(opts []grpc.CallOption) string {
+	key := ""
+	for _, opt := range opts {
+		if o, ok := opt.(pinKeyOption); ok {
+			key = o.key
+		}
+	}
+	return key
+}
+
+// ConnectionOptions tunes how a GRPCConnection spreads calls across its endpoints. Zero fields fall
+// back to DefaultConnectionOptions.
+type ConnectionOptions struct {
+	// HealthCheckInterval is how often every endpoint's latest block is checked. Negative disables
+	// periodic checks; endpoints are then only marked unhealthy when a call returns UNAVAILABLE, and
+	// healthy again when a call succeeds.
+	HealthCheckInterval time.Duration
+
+	// UnhealthyCooldown is, when periodic checks are disabled, how long an endpoint marked unhealthy by
+	// a call is skipped before calls try it again.
+	UnhealthyCooldown time.Duration
+
+	// HealthCheckTimeout bounds a single endpoint's health check.
+	HealthCheckTimeout time.Duration
+
+	// MaxBlockLag is how many blocks an endpoint may trail the highest endpoint and still be healthy.
+	MaxBlockLag int64
+
+	// ChainID, when set, marks endpoints reporting another chain as unhealthy.
+	ChainID string
+
+	// PinnedMethods are full gRPC method names sent to a single endpoint per PinKey until it becomes
+	// unhealthy.
+	PinnedMethods []string
+
+	// TLSConfig is used for TLS endpoints instead of the system roots when set.
+	TLSConfig *tls.Config
+
+	// DialOptions are applied to every endpoint's connection.
+	DialOptions []grpc.DialOption
+
+	// UnaryInterceptors and StreamInterceptors wrap calls before they are routed, outermost first, so a
+	// retrying interceptor may reach a different endpoint on each attempt. They are given a nil
+	// *grpc.ClientConn. Interceptors that should see a single endpoint belong in DialOptions.
+	UnaryInterceptors  []grpc.UnaryClientInterceptor
+	StreamInterceptors []grpc.StreamClientInterceptor
+}
+
+var DefaultConnectionOptions = ConnectionOptions{
+	HealthCheckInterval: 10 * time.Second,
+	HealthCheckTimeout:  3 * time.Second,
+	UnhealthyCooldown:   30 * time.Second,
+	MaxBlockLag:         5,
+	PinnedMethods:       DefaultPinnedMethods,
+}
+
+// EndpointStatus is an endpoint's state as of its last health check or call.
+type EndpointStatus struct {
+	Endpoint
+	Healthy bool
+	Height  int64
+	Err     error
+}
+
+type endpoint struct {
+	Endpoint
+	conn *grpc.ClientConn
+
+	mu      sync.Mutex
+	healthy bool
+	height  int64
+	err     error
+	// changed is when healthy last changed.
+	changed time.Time
+}
+
+func (e *endpoint) isHealthy() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.healthy
+}
+
+// cooledDown reports whether e has been unhealthy for at least d.
+func (e *endpoint) cooledDown(d time.Duration) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !e.healthy && time.Since(e.changed) >= d
+}
+
+func (e *endpoint) setStatus(healthy bool, height int64, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if healthy != e.healthy {
+		e.changed = time.Now()
+	}
+	e.healthy = healthy
+	if height > 0 {
+		e.height = height
+	}
+	e.err = err
+}
+
+// usable reports whether calls may be routed to ep: it is healthy or, without periodic health checks
+// to notice its recovery, it has been unhealthy for UnhealthyCooldown.
+func (c *GRPCConnection) usable(ep *endpoint) bool {
+	return ep.isHealthy() || (!c.healthChecked() && ep.cooledDown(c.opts.UnhealthyCooldown))
+}
+
+// healthChecked reports whether a health loop keeps the endpoints' health up to date.
+func (c *GRPCConnection) healthChecked() bool {
+	return len(c.endpoints) > 1 && c.opts.HealthCheckInterval > 0
+}
+
+// DialEndpoints connects to every endpoint and routes calls between them:
+//   - calls are spread round-robin across healthy endpoints;
+//   - PinnedMethods go to one healthy endpoint per PinKey and stay there until it becomes unhealthy;
+//   - a call failing with UNAVAILABLE marks its endpoint unhealthy and is retried on the next one;
+//   - endpoints are health-checked every HealthCheckInterval, and are unhealthy while their latest block
+//     fails to load or trails the highest endpoint by more than MaxBlockLag.
+//
+// Endpoints start out healthy. When none are healthy, calls go to all of them in turn.
+func DialEndpoints(endpoints []Endpoint, opts ConnectionOptions) (*GRPCConnection, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no grpc endpoints provided")
+	}
+	if opts.HealthCheckInterval == 0 {
+		opts.HealthCheckInterval = DefaultConnectionOptions.HealthCheckInterval
+	}
+	if opts.HealthCheckTimeout <= 0 {
+		opts.HealthCheckTimeout = DefaultConnectionOptions.HealthCheckTimeout
+	}
+	if opts.UnhealthyCooldown <= 0 {
+		opts.UnhealthyCooldown = DefaultConnectionOptions.UnhealthyCooldown
+	}
+	if opts.MaxBlockLag <= 0 {
+		opts.MaxBlockLag = DefaultConnectionOptions.MaxBlockLag
+	}
+	if opts.PinnedMethods == nil {
+		opts.PinnedMethods = DefaultConnectionOptions.PinnedMethods
+	}
+
+	c := &GRPCConnection{
+		opts: opts,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	for _, ep := range endpoints {
+		conn, err := dial(ep, opts.TLSConfig, opts.DialOptions)
+		if err != nil {
+			for _, e := range c.endpoints {
+				e.conn.Close()
+			}
+			return nil, fmt.Errorf("error connecting to %s: %w", ep.URI, err)
+		}
+		c.endpoints = append(c.endpoints, &endpoint{Endpoint: ep, conn: conn, healthy: true})
+	}
+	c.Conn = c.endpoints[0].conn
+
+	// With a single endpoint there is nowhere else to route to.
+	if c.healthChecked() {
+		go c.healthLoop()
+	} else {
+		close(c.done)
+	}
+
+	return c, nil
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *GRPCConnection) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	if c.via != nil {
+		return c.via.Invoke(ctx, method, args, reply, c.withPinKey(opts)...)
+	}
+	if len(c.endpoints) == 0 {
+		return c.Conn.Invoke(ctx, method, args, reply, opts...)
+	}
+	return c.intercept(0, ctx, method, args, reply, opts...)
+}
+
+// withPinKey adds the view's PinKey to opts, where a PinKey of the caller's takes precedence.
+func (c *GRPCConnection) withPinKey(opts []grpc.CallOption) []grpc.CallOption {
+	if c.pinKey == "" {
+		return opts
+	}
+	return append([]grpc.CallOption{PinKey(c.pinKey)}, opts...)
+}
+
+// intercept runs UnaryInterceptors from the i-th on, then routes the call.
+func (c *GRPCConnection) intercept(i int, ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	if i == len(c.opts.UnaryInterceptors) {
+		return c.route(ctx, method, args, reply, opts...)
+	}
+	next := func(ctx context.Context, method string, args, reply any, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return c.intercept(i+1, ctx, method, args, reply, opts...)
+	}
+	return c.opts.UnaryInterceptors[i](ctx, method, args, reply, nil, next, opts...)
+}
+
+// route sends a call to an endpoint, failing over to the others while it is UNAVAILABLE.
+func (c *GRPCConnection) route(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	pinned := slices.Contains(c.opts.PinnedMethods, method)
+	key := pinKeyOf(opts)
+	tried := make([]*endpoint, 0, 1)
+
+	var err error
+	for range c.endpoints {
+		ep := c.pick(pinned, key, tried)
+		err = ep.conn.Invoke(ctx, method, args, reply, opts...)
+		if status.Code(err) != codes.Unavailable || ctx.Err() != nil {
+			if !c.healthChecked() && !ep.isHealthy() {
+				// The node answered again, and no health check is coming to say so.
+				ep.setStatus(true, 0, nil)
+			}
+			return err
+		}
+
+		// The node could not be reached; try the next one.
+		ep.setStatus(false, 0, err)
+		tried = append(tried, ep)
+	}
+	return err
+}
+
+// NewStream implements grpc.ClientConnInterface. Streams are not retried on another endpoint.
+func (c *GRPCConnection) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if c.via != nil {
+		return c.via.NewStream(ctx, desc, method, c.withPinKey(opts)...)
+	}
+	if len(c.endpoints) == 0 {
+		return c.Conn.NewStream(ctx, desc, method, opts...)
+	}
+	return c.interceptStream(0, ctx, desc, method, opts...)
+}
+
+// interceptStream runs StreamInterceptors from the i-th on, then opens the stream on an endpoint.
+func (c *GRPCConnection) interceptStream(i int, ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if i == len(c.opts.StreamInterceptors) {
+		ep := c.pick(slices.Contains(c.opts.PinnedMethods, method), pinKeyOf(opts), nil)
+		return ep.conn.NewStream(ctx, desc, method, opts...)
+	}
+	next := func(ctx context.Context, desc *grpc.StreamDesc, _ *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return c.interceptStream(i+1, ctx, desc, method, opts...)
+	}
+	return c.opts.StreamInterceptors[i](ctx, desc, nil, method, next, opts...)
+}
+
+// pick returns the endpoint for the next call, skipping those in tried. A pinned call goes to the
+// endpoint pinned for key.
+func (c *GRPCConnection) pick(pinned bool, key string, tried []*endpoint) *endpoint {
+	if pinned {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if ep := c.pinned[key]; ep != nil && c.usable(ep) && !slices.Contains(tried, ep) {
+			return ep
+		}
+		if c.pinned == nil {
+			c.pinned = make(map[string]*endpoint)
+		}
+		c.pinned[key] = c.roundRobin(tried)
+		return c.pinned[key]
+	}
+	return c.roundRobin(tried)
+}
+
+// roundRobin returns the next healthy endpoint not in tried, or the next untried one if none are healthy.
+func (c *GRPCConnection) roundRobin(tried []*endpoint) *endpoint {
+	n := uint64(len(c.endpoints))
+	start := c.next.Add(1) - 1
+
+	var fallback *endpoint
+	for i := uint64(0); i < n; i++ {
+		ep := c.endpoints[(start+i)%n]
+		if slices.Contains(tried, ep) {
+			continue
+		}
+		if c.usable(ep) {
+			return ep
+		}
+		if fallback == nil {
+			fallback = ep
+		}
+	}
+	if fallback == nil {
+		// Everything has been tried; start over.
+		return c.endpoints[start%n]
+	}
+	return fallback
+}
+
+func (c *GRPCConnection) healthLoop() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.opts.HealthCheckInterval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.stop
+		cancel()
+	}()
+
+	for {
+		c.CheckHealth(ctx)
+
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckHealth loads every endpoint's latest block and updates which endpoints are healthy. It runs
+// periodically on its own; calling it directly forces a check. On a view it checks the connection the
+// view was made from.
+func (c *GRPCConnection) CheckHealth(ctx context.Context) {
+	if c.parent != nil {
+		c.parent.CheckHealth(ctx)
+		return
+	}
+	if len(c.endpoints) == 0 {
+		return
+	}
+
+	heights := make([]int64, len(c.endpoints))
+	errs := make([]error, len(c.endpoints))
+
+	var wg sync.WaitGroup
+	for i, ep := range c.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			heights[i], errs[i] = c.latestHeight(ctx, ep)
+		}()
+	}
+	wg.Wait()
+
+	maxHeight := slices.Max(heights)
+	for i, ep := range c.endpoints {
+		err := errs[i]
+		if err == nil && heights[i] < maxHeight-c.opts.MaxBlockLag {
+			err = fmt.Errorf("%d blocks behind", maxHeight-heights[i])
+		}
+		ep.setStatus(err == nil, heights[i], err)
+	}
+}
+
+func (c *GRPCConnection) latestHeight(ctx context.Context, ep *endpoint) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.HealthCheckTimeout)
+	defer cancel()
+
+	res, err := tmtypes.NewServiceClient(ep.conn).GetLatestBlock(ctx, &tmtypes.GetLatestBlockRequest{})
+	if err != nil {
+		return 0, err
+	}
+
+	header := res.GetSdkBlock().GetHeader()
+	if header == nil {
+		header = &tmtypes.Header{Height: res.GetBlock().GetHeader().GetHeight(), ChainId: res.GetBlock().GetHeader().GetChainId()}
+	}
+	if c.opts.ChainID != "" && header.ChainId != c.opts.ChainID {
+		return 0, fmt.Errorf("endpoint is on chain %q, expected %q", header.ChainId, c.opts.ChainID)
+	}
+	return header.Height, nil
+}
+
+// Status returns the state of every endpoint, in the order they were given. A view reports the
+// endpoints of the connection it was made from.
+func (c *GRPCConnection) Status() []EndpointStatus {
+	if c.parent != nil {
+		return c.parent.Status()
+	}
+	out := make([]EndpointStatus, 0, len(c.endpoints))
+	for _, ep := range c.endpoints {
+		ep.mu.Lock()
+		out = append(out, EndpointStatus{Endpoint: ep.Endpoint, Healthy: ep.healthy, Height: ep.height, Err: ep.err})
+		ep.mu.Unlock()
+	}
+	return out
+}
@@ -0,0 +1,256 @@
+package provenance
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tmtypes "cosmossdk.io/api/cosmos/base/tendermint/v1beta1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/grpc"
+)
+
+// standInNode is an in-process gRPC server answering the tendermint and tx services as a named node.
+type standInNode struct {
+	tmtypes.UnimplementedServiceServer
+
+	name    string
+	height  atomic.Int64
+	queries atomic.Int64
+	srv     *grpc.Server
+	addr    string
+}
+
+func startStandInNode(t *testing.T, name string, height int64) *standInNode {
+	t.Helper()
+	n := &standInNode{name: name, srv: grpc.NewServer()}
+	n.height.Store(height)
+	tmtypes.RegisterServiceServer(n.srv, n)
+	txtypes.RegisterServiceServer(n.srv, standInTxService{node: n})
+	n.addr = serveTestServer(t, n.srv)
+	return n
+}
+
+// serveTestServer serves srv on a free local port until the test ends and returns its address.
+func serveTestServer(t *testing.T, srv *grpc.Server) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+// serveTestNode starts an in-process gRPC server with the services register adds and connects to it.
+// Both are closed when the test ends.
+func serveTestNode(t *testing.T, register func(*grpc.Server)) *GRPCConnection {
+	t.Helper()
+	srv := grpc.NewServer()
+	register(srv)
+	conn, err := NewGRPCConnection(serveTestServer(t, srv), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func (n *standInNode) GetLatestBlock(context.Context, *tmtypes.GetLatestBlockRequest) (*tmtypes.GetLatestBlockResponse, error) {
+	return &tmtypes.GetLatestBlockResponse{
+		SdkBlock: &tmtypes.Block{Header: &tmtypes.Header{ChainId: "testing", Height: n.height.Load()}},
+	}, nil
+}
+
+func (n *standInNode) GetNodeInfo(context.Context, *tmtypes.GetNodeInfoRequest) (*tmtypes.GetNodeInfoResponse, error) {
+	n.queries.Add(1)
+	return &tmtypes.GetNodeInfoResponse{ApplicationVersion: &tmtypes.VersionInfo{Name: n.name}}, nil
+}
+
+type standInTxService struct {
+	*txtypes.UnimplementedServiceServer
+	node *standInNode
+}
+
+func (s standInTxService) BroadcastTx(context.Context, *txtypes.BroadcastTxRequest) (*txtypes.BroadcastTxResponse, error) {
+	return &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{RawLog: s.node.name}}, nil
+}
+
+func dialStandIns(t *testing.T, nodes ...*standInNode) *GRPCConnection {
+	t.Helper()
+	// Health checks are forced with CheckHealth so the test controls when they happen.
+	return dialStandInsWith(t, ConnectionOptions{HealthCheckInterval: -1, ChainID: "testing"}, nodes...)
+}
+
+func dialStandInsWith(t *testing.T, opts ConnectionOptions, nodes ...*standInNode) *GRPCConnection {
+	t.Helper()
+	endpoints := make([]Endpoint, 0, len(nodes))
+	for _, n := range nodes {
+		endpoints = append(endpoints, Endpoint{URI: n.addr})
+	}
+	conn, err := DialEndpoints(endpoints, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func queryNodeName(t *testing.T, conn *GRPCConnection) string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := tmtypes.NewServiceClient(conn).GetNodeInfo(ctx, &tmtypes.GetNodeInfoRequest{})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	return res.ApplicationVersion.Name
+}
+
+func broadcastNodeName(t *testing.T, conn *GRPCConnection, opts ...grpc.CallOption) string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := txtypes.NewServiceClient(conn).BroadcastTx(ctx, &txtypes.BroadcastTxRequest{}, opts...)
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+	return res.TxResponse.RawLog
+}
+
+func TestEndpointsRoundRobinAndLag(t *testing.T) {
+	t.Parallel()
+	a := startStandInNode(t, "a", 200)
+	b := startStandInNode(t, "b", 199)
+	c := startStandInNode(t, "c", 100)
+	conn := dialStandIns(t, a, b, c)
+
+	for i := 0; i < 6; i++ {
+		queryNodeName(t, conn)
+	}
+	for _, n := range []*standInNode{a, b, c} {
+		if got := n.queries.Load(); got != 2 {
+			t.Fatalf("node %s served %d of 6 queries, want 2", n.name, got)
+		}
+	}
+
+	// c is 100 blocks behind and drops out of the rotation.
+	conn.CheckHealth(context.Background())
+	for i := 0; i < 6; i++ {
+		if name := queryNodeName(t, conn); name == "c" {
+			t.Fatal("query routed to lagging node")
+		}
+	}
+	if st := conn.Status()[2]; st.Healthy || st.Height != 100 || st.Err == nil {
+		t.Fatalf("lagging node status: %+v", st)
+	}
+}
+
+func TestEndpointsFailoverAndPinnedBroadcast(t *testing.T) {
+	t.Parallel()
+	a := startStandInNode(t, "a", 200)
+	b := startStandInNode(t, "b", 200)
+	conn := dialStandIns(t, a, b)
+
+	pinned := broadcastNodeName(t, conn)
+	for i := 0; i < 4; i++ {
+		if got := broadcastNodeName(t, conn); got != pinned {
+			t.Fatalf("broadcast moved from %s to %s", pinned, got)
+		}
+	}
+
+	// Take the pinned node down: queries and broadcasts fail over to the other one and stay there.
+	down, up := a, b
+	if pinned == "b" {
+		down, up = b, a
+	}
+	down.srv.Stop()
+
+	for i := 0; i < 4; i++ {
+		if got := queryNodeName(t, conn); got != up.name {
+			t.Fatalf("query served by %s after %s went down", got, down.name)
+		}
+		if got := broadcastNodeName(t, conn); got != up.name {
+			t.Fatalf("broadcast served by %s after %s went down", got, down.name)
+		}
+	}
+}
+
+func TestEndpointsPinPerSigner(t *testing.T) {
+	t.Parallel()
+	a := startStandInNode(t, "a", 200)
+	b := startStandInNode(t, "b", 200)
+	conn := dialStandIns(t, a, b)
+
+	// Two signers are pinned to a node each; with two nodes round-robin gives them different ones.
+	first := broadcastNodeName(t, conn, PinKey("tp1first"))
+	second := broadcastNodeName(t, conn, PinKey("tp1second"))
+	if first == second {
+		t.Fatalf("both signers pinned to %s", first)
+	}
+
+	// A view for a signer, like a named account's, adds its key to every call.
+	view := conn.view(conn)
+	view.pinKey = "tp1second"
+	for i := 0; i < 4; i++ {
+		if got := broadcastNodeName(t, conn, PinKey("tp1first")); got != first {
+			t.Fatalf("first signer moved from %s to %s", first, got)
+		}
+		if got := broadcastNodeName(t, view); got != second {
+			t.Fatalf("second signer moved from %s to %s", second, got)
+		}
+	}
+}
+
+func TestEndpointsRecoverWithoutHealthChecks(t *testing.T) {
+	t.Parallel()
+	a := startStandInNode(t, "a", 100)
+	b := startStandInNode(t, "b", 100)
+	cooldown := 200 * time.Millisecond
+	conn := dialStandInsWith(t, ConnectionOptions{HealthCheckInterval: -1, UnhealthyCooldown: cooldown}, a, b)
+
+	// As if a call to a had returned UNAVAILABLE.
+	conn.endpoints[0].setStatus(false, 0, errors.New("unavailable"))
+	for i := 0; i < 4; i++ {
+		if name := queryNodeName(t, conn); name != "b" {
+			t.Fatalf("query routed to unhealthy node %s", name)
+		}
+	}
+
+	// Once the cooldown is over a is tried again, and answering makes it healthy.
+	time.Sleep(cooldown)
+	for i := 0; i < 4; i++ {
+		queryNodeName(t, conn)
+	}
+	if a.queries.Load() == 0 || !conn.Status()[0].Healthy {
+		t.Fatalf("a served %d queries after the cooldown, status %+v", a.queries.Load(), conn.Status()[0])
+	}
+}
+
+func TestEndpointsConn(t *testing.T) {
+	t.Parallel()
+	a := startStandInNode(t, "a", 100)
+	b := startStandInNode(t, "b", 100)
+	conn := dialStandIns(t, a, b)
+
+	// Conn stays a *grpc.ClientConn on the first endpoint.
+	if got := conn.Conn.Target(); got != a.addr {
+		t.Fatalf("got target %q want %q", got, a.addr)
+	}
+
+	// A GRPCConnection built around a single Conn, as before DialEndpoints, still works.
+	cc, err := dial(Endpoint{URI: b.addr}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	single := &GRPCConnection{Conn: cc}
+	defer single.Close()
+	if got := queryNodeName(t, single); got != "b" {
+		t.Fatalf("got %q want b", got)
+	}
+}
@@ -0,0 +1,145 @@
+package provenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Errors for common chain failures. Errors from the client's gRPC calls and *TxFailedError values
+// match them with errors.Is.
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrSequenceMismatch = errors.New("account sequence mismatch")
+	ErrInsufficientFee  = errors.New("insufficient fee")
+	ErrOutOfGas         = errors.New("out of gas")
+)
+
+// abciErrors maps SDK ABCI errors to the errors above. Their descriptions are also matched in gRPC
+// error messages, since simulation and query failures only carry the ABCI error as text.
+var abciErrors = []struct {
+	abci *errorsmod.Error
+	err  error
+}{
+	{sdkerrors.ErrWrongSequence, ErrSequenceMismatch},
+	{sdkerrors.ErrInsufficientFee, ErrInsufficientFee},
+	{sdkerrors.ErrOutOfGas, ErrOutOfGas},
+	{authz.ErrNoAuthorizationFound, ErrNotFound},
+}
+
+// abciError returns the error for an ABCI codespace/code pair, or nil if it has none.
+func abciError(codespace string, code uint32) error {
+	for _, e := range abciErrors {
+		if e.abci.Codespace() == codespace && e.abci.ABCICode() == code {
+			return e.err
+		}
+	}
+	switch {
+	case codespace == sdkerrors.ErrNotFound.Codespace() && code == sdkerrors.ErrNotFound.ABCICode(),
+		codespace == sdkerrors.ErrKeyNotFound.Codespace() && code == sdkerrors.ErrKeyNotFound.ABCICode():
+		return ErrNotFound
+	}
+	return nil
+}
+
+// TxFailedError is a transaction that CheckTx rejected or that failed once included in a block.
+type TxFailedError struct {
+	TxHash    string
+	Code      uint32
+	Codespace string
+	RawLog    string
+
+	// CheckTx is set when the node rejected the transaction before it was included in a block.
+	CheckTx bool
+}
+
+// NewTxFailedError returns the failure for a TxResponse with a non-zero code.
+func NewTxFailedError(txr *sdk.TxResponse, checkTx bool) *TxFailedError {
+	return &TxFailedError{
+		TxHash:    txr.TxHash,
+		Code:      txr.Code,
+		Codespace: txr.Codespace,
+		RawLog:    txr.RawLog,
+		CheckTx:   checkTx,
+	}
+}
+
+func (e *TxFailedError) Error() string {
+	if e.CheckTx {
+		return fmt.Sprintf("check tx failed code=%d codespace=%s raw_log=%s", e.Code, e.Codespace, e.RawLog)
+	}
+	return fmt.Sprintf("tx %s failed code=%d codespace=%s raw_log=%s", e.TxHash, e.Code, e.Codespace, e.RawLog)
+}
+
+// Is matches the error for the transaction's ABCI codespace and code, e.g. ErrOutOfGas.
+func (e *TxFailedError) Is(target error) bool {
+	kind := abciError(e.Codespace, e.Code)
+	return kind != nil && kind == target
+}
+
+// chainError is a gRPC error that also matches one of the errors above.
+type chainError struct {
+	err  error
+	kind error
+}
+
+func (e *chainError) Error() string   { return e.err.Error() }
+func (e *chainError) Unwrap() []error { return []error{e.err, e.kind} }
+
+// ClassifyError makes a gRPC error from a node match ErrNotFound, ErrAlreadyExists,
+// ErrSequenceMismatch, ErrInsufficientFee or ErrOutOfGas with errors.Is, based on its status code and
+// the SDK error it carries. The gRPC status stays available to status.Code. Errors that match none of
+// them are returned as-is. The client's own calls are classified already; this is for connections
+// made some other way.
+func ClassifyError(err error) error {
+	var ce *chainError
+	if err == nil || errors.As(err, &ce) {
+		return err
+	}
+
+	var kind error
+	switch status.Code(err) {
+	case codes.NotFound:
+		kind = ErrNotFound
+	case codes.AlreadyExists:
+		kind = ErrAlreadyExists
+	default:
+		msg := err.Error()
+		for _, e := range abciErrors {
+			if strings.Contains(msg, e.abci.Error()) {
+				kind = e.err
+				break
+			}
+		}
+		if kind == nil && strings.Contains(msg, ErrSequenceMismatch.Error()) {
+			kind = ErrSequenceMismatch
+		}
+	}
+	if kind == nil {
+		return err
+	}
+	return &chainError{err: err, kind: kind}
+}
+
+// classifyInterceptor applies ClassifyError to every call's error.
+func classifyInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return ClassifyError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// txResultErr returns nil for a successful transaction and a *TxFailedError otherwise.
+func txResultErr(txr *sdk.TxResponse) error {
+	if txr.Code == 0 {
+		return nil
+	}
+	return NewTxFailedError(txr, false)
+}
@@ -0,0 +1,65 @@
+package provenance
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTxFailedError(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		codespace string
+		code      uint32
+		want      error
+	}{
+		{"sdk", 32, ErrSequenceMismatch},
+		{"sdk", 13, ErrInsufficientFee},
+		{"sdk", 11, ErrOutOfGas},
+		{"sdk", 38, ErrNotFound},
+		{"wasm", 5, nil},
+	}
+	for _, tc := range cases {
+		txr := &sdk.TxResponse{TxHash: "ABC", Code: tc.code, Codespace: tc.codespace}
+		err := fmt.Errorf("pool: %w", NewTxFailedError(txr, false))
+
+		var txErr *TxFailedError
+		if !errors.As(err, &txErr) || txErr.Code != tc.code || txErr.TxHash != "ABC" {
+			t.Fatalf("%s/%d: errors.As gave %+v", tc.codespace, tc.code, txErr)
+		}
+		for _, sentinel := range []error{ErrSequenceMismatch, ErrInsufficientFee, ErrOutOfGas, ErrNotFound} {
+			if got := errors.Is(err, sentinel); got != (sentinel == tc.want) {
+				t.Fatalf("%s/%d: errors.Is(%v) = %v", tc.codespace, tc.code, sentinel, got)
+			}
+		}
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		err  error
+		want error
+	}{
+		{status.Error(codes.NotFound, "tx not found"), ErrNotFound},
+		{status.Error(codes.Unknown, "account sequence mismatch, expected 12, got 11: incorrect account sequence"), ErrSequenceMismatch},
+		{status.Error(codes.Unknown, "out of gas in location: WriteFlat; gasWanted: 10, gasUsed: 11: out of gas"), ErrOutOfGas},
+		{status.Error(codes.Unknown, "authorization not found"), ErrNotFound},
+		{status.Error(codes.Unavailable, "connection refused"), nil},
+	}
+	for _, tc := range cases {
+		err := fmt.Errorf("query: %w", ClassifyError(tc.err))
+		for _, sentinel := range []error{ErrNotFound, ErrSequenceMismatch, ErrOutOfGas} {
+			if got := errors.Is(err, sentinel); got != (sentinel == tc.want) {
+				t.Fatalf("%v: errors.Is(%v) = %v", tc.err, sentinel, got)
+			}
+		}
+		if status.Code(err) != status.Code(tc.err) {
+			t.Fatalf("%v: status code changed to %s", tc.err, status.Code(err))
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	flatfees "github.com/provenance-io/provenance/x/flatfees/types"
+	msgfees "github.com/provenance-io/provenance/x/msgfees/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FeeEstimate is the gas limit and fee SignTx applies to a transaction.
+type FeeEstimate struct {
+	// GasUsed is the simulated gas used, or 0 when an explicit gas limit was given.
+	GasUsed uint64
+	// GasLimit is GasUsed multiplied by the gas adjustment, or the explicit gas limit.
+	GasLimit uint64
+	// GasFee is GasLimit multiplied by the configured gas price.
+	GasFee sdk.Coins
+	// MsgFees are the Provenance msg-based fees charged for the messages in the transaction.
+	MsgFees sdk.Coins
+	// Fee is the total fee to set on the transaction.
+	Fee sdk.Coins
+}
+
+// EstimateFee is EstimateFeeContext with a background context.
+//
+// Deprecated: use EstimateFeeContext.
+func (c *ProvenanceClient) EstimateFee(txConfig client.TxConfig, txBuilder client.TxBuilder, opts TxOptions) (*FeeEstimate, error) {
+	return c.EstimateFeeContext(context.Background(), txConfig, txBuilder, opts)
+}
+
+// EstimateFeeContext computes the gas limit and fee for the transaction in txBuilder. The gas limit is the
+// simulated gas used times opts.GasAdjustment unless opts.GasLimit is set, and the gas fee is the gas
+// limit times BcConfig.GasPrice(). Msg-based fees are then added on top:
+//   - on chains running x/flatfees, the flat cost of the msgs replaces the gas fee wherever it is larger;
+//   - on chains running x/msgfees, the additional msg fees are added to the gas fee.
+//
+// opts.AdditionalFee is added to the result. When opts.FixedFee is set it is used as the fee as-is
+// and msg fees are not queried. txBuilder must already carry the signer's (possibly empty) signatures so
+// that simulation accounts for them.
+func (c *ProvenanceClient) EstimateFeeContext(ctx context.Context, txConfig client.TxConfig, txBuilder client.TxBuilder, opts TxOptions) (*FeeEstimate, error) {
+	est := &FeeEstimate{GasLimit: opts.GasLimit}
+
+	if est.GasLimit == 0 {
+		gasUsed, _, err := SimulateTxContext(ctx, c.Grpc, txConfig, txBuilder)
+		if err != nil {
+			return nil, err
+		}
+		est.GasUsed = gasUsed
+		est.GasLimit = uint64(math.Ceil(float64(gasUsed) * opts.GasAdjustment))
+	}
+	est.GasFee = sdk.NewCoins(sdk.NewCoin(c.BcConfig.Denom(), sdkmath.NewIntFromUint64(est.GasLimit).MulRaw(c.BcConfig.GasPrice())))
+
+	if opts.FixedFee != nil {
+		est.Fee = opts.FixedFee
+		return est, nil
+	}
+
+	// Msg fees are computed against the tx with its final gas limit.
+	txBuilder.SetGasLimit(est.GasLimit)
+	txBz, err := txConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tx: %w", err)
+	}
+
+	flat, err := (*c.FlatFeesClient()).CalculateTxFees(ctx, &flatfees.QueryCalculateTxFeesRequest{
+		TxBytes:       txBz,
+		GasAdjustment: float32(opts.GasAdjustment),
+	})
+	switch {
+	case err == nil:
+		est.MsgFees = flat.TotalFees
+		est.Fee = est.GasFee.Max(flat.TotalFees)
+	case status.Code(err) == codes.Unimplemented:
+		// Older chains charge msg fees on top of gas through x/msgfees.
+		additional, err := c.legacyMsgFees(ctx, txBz, opts.GasAdjustment)
+		if err != nil {
+			return nil, err
+		}
+		est.MsgFees = additional
+		est.Fee = est.GasFee.Add(additional...)
+	default:
+		return nil, fmt.Errorf("calculate tx fees failed: %w", err)
+	}
+
+	est.Fee = est.Fee.Add(opts.AdditionalFee...)
+	return est, nil
+}
+
+// legacyMsgFees returns the additional x/msgfees fees for txBz, or no fees if the module is not present.
+func (c *ProvenanceClient) legacyMsgFees(ctx context.Context, txBz []byte, gasAdjustment float64) (sdk.Coins, error) {
+	res, err := (*c.MsgFeesClient()).CalculateTxFees(ctx, &msgfees.CalculateTxFeesRequest{
+		TxBytes:          txBz,
+		DefaultBaseDenom: c.BcConfig.Denom(),
+		GasAdjustment:    float32(gasAdjustment),
+	})
+	if status.Code(err) == codes.Unimplemented {
+		return sdk.NewCoins(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("calculate msg fees failed: %w", err)
+	}
+	return res.AdditionalFees, nil
+}
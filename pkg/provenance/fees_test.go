@@ -0,0 +1,158 @@
+package provenance
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	flatfees "github.com/provenance-io/provenance/x/flatfees/types"
+	msgfees "github.com/provenance-io/provenance/x/msgfees/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// feeNode simulates every tx at 1000 gas and answers fee queries with fixed amounts.
+type feeNode struct {
+	*txtypes.UnimplementedServiceServer
+}
+
+func (feeNode) Simulate(context.Context, *txtypes.SimulateRequest) (*txtypes.SimulateResponse, error) {
+	return &txtypes.SimulateResponse{GasInfo: &sdk.GasInfo{GasUsed: 1000}}, nil
+}
+
+type flatFeesNode struct {
+	*flatfees.UnimplementedQueryServer
+	fees sdk.Coins
+	err  error
+}
+
+func (n flatFeesNode) CalculateTxFees(context.Context, *flatfees.QueryCalculateTxFeesRequest) (*flatfees.QueryCalculateTxFeesResponse, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	return &flatfees.QueryCalculateTxFeesResponse{TotalFees: n.fees}, nil
+}
+
+type msgFeesNode struct {
+	*msgfees.UnimplementedQueryServer
+	additional sdk.Coins
+}
+
+func (n msgFeesNode) CalculateTxFees(context.Context, *msgfees.CalculateTxFeesRequest) (*msgfees.CalculateTxFeesResponse, error) {
+	return &msgfees.CalculateTxFeesResponse{AdditionalFees: n.additional}, nil
+}
+
+func TestEstimateFee(t *testing.T) {
+	t.Parallel()
+	nhash := func(amount int64) sdk.Coins { return sdk.NewCoins(sdk.NewInt64Coin("nhash", amount)) }
+
+	cases := []struct {
+		name     string
+		register func(*grpc.Server)
+		opts     TxOptions
+		gasUsed  uint64
+		msgFees  sdk.Coins
+		fee      sdk.Coins
+	}{
+		{
+			name: "flatfees above gas fee",
+			register: func(srv *grpc.Server) {
+				flatfees.RegisterQueryServer(srv, flatFeesNode{fees: nhash(2000)})
+			},
+			opts:    NewTxOptions(),
+			gasUsed: 1000,
+			msgFees: nhash(2000),
+			fee:     nhash(2000),
+		},
+		{
+			name: "flatfees below gas fee",
+			register: func(srv *grpc.Server) {
+				flatfees.RegisterQueryServer(srv, flatFeesNode{fees: nhash(100)})
+			},
+			opts:    NewTxOptions(),
+			gasUsed: 1000,
+			msgFees: nhash(100),
+			fee:     nhash(1500),
+		},
+		{
+			// Without x/flatfees the node answers Unimplemented and x/msgfees is asked instead.
+			name: "msgfees fallback",
+			register: func(srv *grpc.Server) {
+				msgfees.RegisterQueryServer(srv, msgFeesNode{additional: nhash(300)})
+			},
+			opts:    NewTxOptions(),
+			gasUsed: 1000,
+			msgFees: nhash(300),
+			fee:     nhash(1800),
+		},
+		{
+			name:     "no msg fee module",
+			register: func(*grpc.Server) {},
+			opts:     NewTxOptions(),
+			gasUsed:  1000,
+			msgFees:  sdk.NewCoins(),
+			fee:      nhash(1500),
+		},
+		{
+			// Msg fees are not queried at all.
+			name: "fixed fee",
+			register: func(srv *grpc.Server) {
+				flatfees.RegisterQueryServer(srv, flatFeesNode{err: status.Error(codes.Internal, "unexpected query")})
+			},
+			opts:    NewTxOptions(WithFixedFee(nhash(42))),
+			gasUsed: 1000,
+			fee:     nhash(42),
+		},
+		{
+			name: "additional fee and gas limit",
+			register: func(srv *grpc.Server) {
+				flatfees.RegisterQueryServer(srv, flatFeesNode{fees: nhash(100)})
+			},
+			opts:    NewTxOptions(WithGasLimit(3000), WithAdditionalFee(sdk.NewCoins(sdk.NewInt64Coin("usd", 5)))),
+			msgFees: nhash(100),
+			fee:     nhash(3000).Add(sdk.NewInt64Coin("usd", 5)),
+		},
+	}
+	for _, tc := range cases {
+		conn := serveTestNode(t, func(srv *grpc.Server) {
+			txtypes.RegisterServiceServer(srv, feeNode{})
+			tc.register(srv)
+		})
+		c := &ProvenanceClient{Grpc: conn, BcConfig: NewLocalnetConfig()}
+		txConfig := NewTxConfig()
+
+		est, err := c.EstimateFeeContext(context.Background(), txConfig, txConfig.NewTxBuilder(), tc.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if est.GasUsed != tc.gasUsed || !est.MsgFees.Equal(tc.msgFees) || !est.Fee.Equal(tc.fee) {
+			t.Fatalf("%s: got gas used %d, msg fees %s, fee %s want %d, %s, %s", tc.name, est.GasUsed, est.MsgFees, est.Fee, tc.gasUsed, tc.msgFees, tc.fee)
+		}
+	}
+
+	// Gas fees past int64 do not overflow.
+	c := &ProvenanceClient{BcConfig: NewLocalnetConfig()}
+	txConfig := NewTxConfig()
+	est, err := c.EstimateFeeContext(context.Background(), txConfig, txConfig.NewTxBuilder(), NewTxOptions(WithGasLimit(math.MaxUint64), WithFixedFee(nhash(1))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sdkmath.NewIntFromUint64(math.MaxUint64).MulRaw(c.BcConfig.GasPrice())
+	if !est.GasFee.AmountOf("nhash").Equal(want) {
+		t.Fatalf("gas fee %s, want %snhash", est.GasFee, want)
+	}
+
+	// Other errors from x/flatfees are not papered over.
+	conn := serveTestNode(t, func(srv *grpc.Server) {
+		txtypes.RegisterServiceServer(srv, feeNode{})
+		flatfees.RegisterQueryServer(srv, flatFeesNode{err: status.Error(codes.Internal, "boom")})
+	})
+	c = &ProvenanceClient{Grpc: conn, BcConfig: NewLocalnetConfig()}
+	if _, err := c.EstimateFeeContext(context.Background(), txConfig, txConfig.NewTxBuilder(), NewTxOptions()); status.Code(err) != codes.Internal {
+		t.Fatalf("got %v want %v", err, codes.Internal)
+	}
+}
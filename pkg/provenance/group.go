@@ -1,6 +1,8 @@
 package provenance
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	grouptypes "github.com/cosmos/cosmos-sdk/x/group"
 )
@@ -10,6 +12,13 @@ import (
 // populate GroupPolicyAddress, Title, Summary, Metadata, and Exec on the
 // returned message before signing/broadcasting.
 func (c *ProvenanceClient) WrapGroupProposal(groupPolicyAddress, metadata, title, summary string, msgs ...sdk.Msg) (*grouptypes.MsgSubmitProposal, error) {
+	if _, err := c.ParseAddress(groupPolicyAddress); err != nil {
+		return nil, fmt.Errorf("group policy address: %w", err)
+	}
+	if _, err := c.ParseAddress(c.Address); err != nil {
+		return nil, fmt.Errorf("proposer address: %w", err)
+	}
+
 	proposal := &grouptypes.MsgSubmitProposal{
 		GroupPolicyAddress: groupPolicyAddress,
 		Metadata:           metadata,
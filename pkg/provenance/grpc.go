@@ -1,9 +1,24 @@
 package provenance
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	bankv1beta1 "cosmossdk.io/api/cosmos/bank/v1beta1"
+	"cosmossdk.io/x/tx/signing"
+	"cosmossdk.io/x/tx/signing/textual"
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
+	addresscodec "github.com/cosmos/cosmos-sdk/codec/address"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/cosmos/gogoproto/proto"
 
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
@@ -21,39 +36,149 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// GRPCConnection is a connection to one or more Provenance nodes. It implements grpc.ClientConnInterface,
+// so generated gRPC clients can be built on it directly; see DialEndpoints for how calls are routed.
 type GRPCConnection struct {
+	// Conn is the connection to the first endpoint. Calls made on it go to that node only and skip
+	// failover, the call policy and error classification; build generated clients on the GRPCConnection
+	// itself to get those. It is kept for its connection state, e.g. GetState and Target.
 	Conn *grpc.ClientConn
+
+	// via, when set, carries every call instead of the endpoints, e.g. for an AtHeight view. parent is
+	// the connection the view was made from, which reports health and status for it.
+	via    grpc.ClientConnInterface
+	parent *GRPCConnection
+	// pinKey is added to every call as a PinKey, e.g. by the view of a named account.
+	pinKey string
+
+	opts      ConnectionOptions
+	endpoints []*endpoint
+	next      atomic.Uint64
+
+	// pinned holds the endpoint that currently receives PinnedMethods for each PinKey.
+	mu     sync.Mutex
+	pinned map[string]*endpoint
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
+// Verify that the GRPCConnection implements the grpc.ClientConnInterface interface
+var _ grpc.ClientConnInterface = (*GRPCConnection)(nil)
+
+// NewGRPCConnection connects to a single node.
 func NewGRPCConnection(uri string, secure bool) (*GRPCConnection, error) {
-	// Use system TLS credentials for gRPCs connections, skipping certFile check
+	return DialEndpoints([]Endpoint{{URI: uri, TLS: secure}}, ConnectionOptions{})
+}
+
+func dial(ep Endpoint, tlsConf *tls.Config, dialOpts []grpc.DialOption) (*grpc.ClientConn, error) {
+	// Use system TLS credentials for gRPCs connections unless a TLS config is given
 	// For insecure connections, use insecure credentials
 	var creds credentials.TransportCredentials
-	if secure {
-		creds = credentials.NewTLS(nil)
+	if ep.TLS {
+		creds = credentials.NewTLS(tlsConf)
 	} else {
 		creds = insecure.NewCredentials()
 	}
 
-	grpcConn, err := grpc.NewClient(uri, grpc.WithTransportCredentials(creds))
-	if err != nil {
-		return nil, err
-	}
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, dialOpts...)
+	return grpc.NewClient(ep.URI, opts...)
+}
 
-	g := GRPCConnection{
-		Conn: grpcConn,
+// view returns a connection that sends every call through via and otherwise stands for c: it shares
+// c's endpoints, health and status, and closing it leaves c open.
+func (c *GRPCConnection) view(via grpc.ClientConnInterface) *GRPCConnection {
+	pinKey := c.pinKey
+	if c.parent != nil {
+		c = c.parent
 	}
-
-	return &g, nil
+	return &GRPCConnection{Conn: c.Conn, via: via, parent: c, pinKey: pinKey}
 }
 
+// Close stops health checking and closes the connection to every endpoint.
 func (c *GRPCConnection) Close() error {
-	return c.Conn.Close()
+	if c.via != nil {
+		// A view of another connection, e.g. an AtHeight view's; there is nothing to close.
+		return nil
+	}
+	if c.stop == nil {
+		// Built around a single Conn rather than dialed by DialEndpoints.
+		return c.Conn.Close()
+	}
+	c.closeOnce.Do(func() { close(c.stop) })
+	<-c.done
+
+	var errs []error
+	for _, ep := range c.endpoints {
+		if err := ep.conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ep.URI, err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
+// Codec returns a codec with the provenance interfaces registered. It has no address codec, so it can
+// encode and decode but not resolve msg signers; use NewCodec for that.
 func Codec() *codec.ProtoCodec {
 	reg := codectypes.NewInterfaceRegistry()
+	registerInterfaces(reg)
+
+	cdc := codec.NewProtoCodec(reg)
+	return cdc
+}
 
+// NewCodec returns a codec with the provenance interfaces registered whose signing context encodes
+// account addresses with addressPrefix and validator addresses with addressPrefix+"valoper". It
+// does not depend on the global SDK config.
+func NewCodec(addressPrefix string) (*codec.ProtoCodec, error) {
+	reg, err := codectypes.NewInterfaceRegistryWithOptions(codectypes.InterfaceRegistryOptions{
+		ProtoFiles: proto.HybridResolver,
+		SigningOptions: signing.Options{
+			AddressCodec:          addresscodec.NewBech32Codec(addressPrefix),
+			ValidatorAddressCodec: addresscodec.NewBech32Codec(addressPrefix + sdk.PrefixValidator + sdk.PrefixOperator),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating interface registry: %w", err)
+	}
+	registerInterfaces(reg)
+
+	return codec.NewProtoCodec(reg), nil
+}
+
+// LegacyAmino returns a legacy amino codec with the SDK, bank, authz, group and wasm amino types
+// registered, along with the marker, metadata, attribute and registry messages. Provenance gives its
+// messages no amino names, so they are registered under their type URLs, which is also what
+// SIGN_MODE_LEGACY_AMINO_JSON calls them in sign bytes.
+//
+// The client does not use it: amino JSON sign bytes come from the x/tx aminojson encoder. It is for
+// callers that need legacy amino JSON themselves, such as multisig public keys for older tools.
+func LegacyAmino() *codec.LegacyAmino {
+	cdc := codec.NewLegacyAmino()
+	sdk.RegisterLegacyAminoCodec(cdc)
+	cryptocodec.RegisterCrypto(cdc)
+	banktypes.RegisterLegacyAminoCodec(cdc)
+	authztypes.RegisterLegacyAminoCodec(cdc)
+	grouptypes.RegisterLegacyAminoCodec(cdc)
+	wasmtypes.RegisterLegacyAminoCodec(cdc)
+
+	var msgs []sdk.Msg
+	msgs = append(msgs, marker.AllRequestMsgs...)
+	for _, msg := range meta.AllRequestMsgs {
+		msgs = append(msgs, msg)
+	}
+	msgs = append(msgs, attrtypes.AllRequestMsgs...)
+	msgs = append(msgs, registry.AllRequestMsgs...)
+	for _, msg := range msgs {
+		cdc.RegisterConcrete(msg, "/"+proto.MessageName(msg), nil)
+	}
+
+	cdc.Seal()
+	return cdc
+}
+
+func registerInterfaces(reg codectypes.InterfaceRegistry) {
 	cryptocodec.RegisterInterfaces(reg)
 	marker.RegisterInterfaces(reg)
 	meta.RegisterInterfaces(reg)
@@ -63,9 +188,6 @@ func Codec() *codec.ProtoCodec {
 	authztypes.RegisterInterfaces(reg)
 	grouptypes.RegisterInterfaces(reg)
 	wasmtypes.RegisterInterfaces(reg)
-
-	cdc := codec.NewProtoCodec(reg)
-	return cdc
 }
 
 // Create a new tx config with the appropriate provenance interfaces registered
@@ -73,3 +195,27 @@ func NewTxConfig() client.TxConfig {
 	txConfig := authtx.NewTxConfig(Codec(), authtx.DefaultSignModes)
 	return txConfig
 }
+
+// newTxConfig returns a tx config for cdc that, besides the default sign modes, supports
+// SIGN_MODE_TEXTUAL, rendering coins with denom metadata queried through conn.
+func newTxConfig(cdc codec.Codec, conn grpc.ClientConnInterface) (client.TxConfig, error) {
+	return authtx.NewTxConfigWithOptions(cdc, authtx.ConfigOptions{
+		EnabledSignModes:           append(slices.Clone(authtx.DefaultSignModes), signingtypes.SignMode_SIGN_MODE_TEXTUAL),
+		TextualCoinMetadataQueryFn: coinMetadataQueryFn(conn),
+	})
+}
+
+// coinMetadataQueryFn looks denom metadata up for SIGN_MODE_TEXTUAL. Denoms without metadata are
+// rendered in base units.
+func coinMetadataQueryFn(conn grpc.ClientConnInterface) textual.CoinMetadataQueryFn {
+	return func(ctx context.Context, denom string) (*bankv1beta1.Metadata, error) {
+		res, err := bankv1beta1.NewQueryClient(conn).DenomMetadata(ctx, &bankv1beta1.QueryDenomMetadataRequest{Denom: denom})
+		if errors.Is(ClassifyError(err), ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return res.Metadata, nil
+	}
+}
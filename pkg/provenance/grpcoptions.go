@@ -0,0 +1,131 @@
+package provenance
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+)
+
+// GRPCOptions customizes how the client dials its nodes. The zero value dials with system TLS roots
+// (or insecure, per endpoint) and gRPC's defaults.
+type GRPCOptions struct {
+	// CAFile is a PEM bundle of CAs trusted for TLS endpoints instead of the system roots.
+	CAFile string
+
+	// CertFile and KeyFile are a PEM client certificate and key presented to TLS endpoints (mTLS).
+	CertFile string
+	KeyFile  string
+
+	// Headers are sent as metadata on every call.
+	Headers map[string]string
+
+	// BearerToken is sent as an "authorization: Bearer <token>" header on every call.
+	BearerToken string
+
+	// KeepaliveTime is how long the connection may be idle before it is pinged. 0 disables keepalive pings.
+	KeepaliveTime time.Duration
+	// KeepaliveTimeout is how long to wait for a ping ack before the connection is closed.
+	KeepaliveTimeout time.Duration
+	// KeepalivePermitWithoutStream pings even when there are no active calls.
+	KeepalivePermitWithoutStream bool
+
+	// MaxRecvMsgSize raises the largest response accepted, in bytes, from gRPC's 4MB default.
+	MaxRecvMsgSize int
+
+	// UnaryInterceptors and StreamInterceptors wrap every call, outermost first.
+	UnaryInterceptors  []grpc.UnaryClientInterceptor
+	StreamInterceptors []grpc.StreamClientInterceptor
+}
+
+// tlsConfig returns the TLS config for TLS endpoints, or nil to use the system roots.
+func (o GRPCOptions) tlsConfig() (*tls.Config, error) {
+	if o.CAFile == "" && o.CertFile == "" && o.KeyFile == "" {
+		return nil, nil
+	}
+
+	conf := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", o.CAFile)
+		}
+		conf.RootCAs = pool
+	}
+
+	if o.CertFile != "" || o.KeyFile != "" {
+		if o.CertFile == "" || o.KeyFile == "" {
+			return nil, fmt.Errorf("client certificate and key must be provided together")
+		}
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	return conf, nil
+}
+
+// dialOptions returns the dial options for everything other than transport credentials.
+func (o GRPCOptions) dialOptions() []grpc.DialOption {
+	var opts []grpc.DialOption
+
+	var pairs []string
+	for k, v := range o.Headers {
+		pairs = append(pairs, k, v)
+	}
+	if o.BearerToken != "" {
+		pairs = append(pairs, "authorization", "Bearer "+o.BearerToken)
+	}
+	if len(pairs) > 0 {
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(headerUnaryInterceptor(pairs)),
+			grpc.WithChainStreamInterceptor(headerStreamInterceptor(pairs)),
+		)
+	}
+
+	if len(o.UnaryInterceptors) > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(o.UnaryInterceptors...))
+	}
+	if len(o.StreamInterceptors) > 0 {
+		opts = append(opts, grpc.WithChainStreamInterceptor(o.StreamInterceptors...))
+	}
+
+	if o.KeepaliveTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                o.KeepaliveTime,
+			Timeout:             o.KeepaliveTimeout,
+			PermitWithoutStream: o.KeepalivePermitWithoutStream,
+		}))
+	}
+
+	if o.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(o.MaxRecvMsgSize)))
+	}
+
+	return opts
+}
+
+func headerUnaryInterceptor(pairs []string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(metadata.AppendToOutgoingContext(ctx, pairs...), method, req, reply, cc, opts...)
+	}
+}
+
+func headerStreamInterceptor(pairs []string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(metadata.AppendToOutgoingContext(ctx, pairs...), desc, cc, method, opts...)
+	}
+}
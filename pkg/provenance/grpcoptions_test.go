@@ -0,0 +1,182 @@
+package provenance
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tmtypes "cosmossdk.io/api/cosmos/base/tendermint/v1beta1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// mtlsNode is a TLS-only tendermint service that requires a client certificate and records the
+// authorization header of the last call.
+type mtlsNode struct {
+	tmtypes.UnimplementedServiceServer
+
+	mu   sync.Mutex
+	auth string
+}
+
+func (n *mtlsNode) GetNodeInfo(ctx context.Context, _ *tmtypes.GetNodeInfoRequest) (*tmtypes.GetNodeInfoResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	n.mu.Lock()
+	n.auth = strings.Join(md.Get("authorization"), ",")
+	n.mu.Unlock()
+	return &tmtypes.GetNodeInfoResponse{ApplicationVersion: &tmtypes.VersionInfo{Name: strings.Repeat("x", 1024)}}, nil
+}
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func issueCert(t *testing.T, cn string, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// write stores the certificate and key as PEM files and returns their paths.
+func (c *testCert) write(t *testing.T, dir, name string) (string, string) {
+	t.Helper()
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPath, keyPath := filepath.Join(dir, name+".pem"), filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath
+}
+
+func TestGRPCOptionsMutualTLS(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	ca := issueCert(t, "test ca", nil)
+	server := issueCert(t, "node", ca)
+	client := issueCert(t, "client", ca)
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := client.write(t, dir, "client")
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	node := &mtlsNode{}
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{server.cert.Raw}, PrivateKey: server.key}},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	tmtypes.RegisterServiceServer(srv, node)
+	addr := serveTestServer(t, srv)
+
+	query := func(o GRPCOptions) error {
+		tlsConf, err := o.tlsConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn, err := DialEndpoints([]Endpoint{{URI: addr, TLS: true}}, ConnectionOptions{
+			TLSConfig:   tlsConf,
+			DialOptions: o.dialOptions(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = tmtypes.NewServiceClient(conn).GetNodeInfo(ctx, &tmtypes.GetNodeInfoRequest{})
+		return err
+	}
+
+	opts := GRPCOptions{CAFile: caFile, CertFile: certFile, KeyFile: keyFile, BearerToken: "s3cret"}
+	if err := query(opts); err != nil {
+		t.Fatalf("mTLS query: %v", err)
+	}
+	node.mu.Lock()
+	auth := node.auth
+	node.mu.Unlock()
+	if auth != "Bearer s3cret" {
+		t.Fatalf("server saw authorization %q", auth)
+	}
+
+	if err := query(GRPCOptions{CAFile: caFile}); err == nil {
+		t.Fatal("expected query without a client certificate to fail")
+	}
+
+	opts.MaxRecvMsgSize = 64
+	if err := query(opts); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted for an oversized response, got %v", err)
+	}
+}
+
+func TestGRPCSpecFromEnv(t *testing.T) {
+	t.Parallel()
+	env := map[string]string{
+		"PROV_NETWORK":        "localnet",
+		"PROV_GRPC_TOKEN":     "s3cret",
+		"PROV_GRPC_KEEPALIVE": "30s",
+		"PROV_GRPC_MAX_RECV":  "16777216",
+	}
+	spec, err := specFromEnv(func(k string) (string, bool) { v, ok := env[k]; return v, ok })
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := spec.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := c.GRPCOptions()
+	if o.BearerToken != "s3cret" || o.KeepaliveTime != 30*time.Second || o.MaxRecvMsgSize != 16<<20 {
+		t.Fatalf("unexpected grpc options %+v", o)
+	}
+}
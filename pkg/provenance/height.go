@@ -0,0 +1,86 @@
+package provenance
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// HeightConn sends every call through conn pinned to a block height, and records the height the node
+// reports having answered at.
+type HeightConn struct {
+	conn   grpc.ClientConnInterface
+	height int64
+	last   atomic.Int64
+}
+
+// Verify that the HeightConn implements the grpc.ClientConnInterface interface
+var _ grpc.ClientConnInterface = (*HeightConn)(nil)
+
+// NewHeightConn pins calls through conn to height. A height of 0 leaves calls at the latest block but
+// still records the height they were answered at.
+func NewHeightConn(conn grpc.ClientConnInterface, height int64) *HeightConn {
+	return &HeightConn{conn: conn, height: height}
+}
+
+func (c *HeightConn) pin(ctx context.Context) context.Context {
+	if c.height <= 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(c.height, 10))
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *HeightConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	var header metadata.MD
+	err := c.conn.Invoke(c.pin(ctx), method, args, reply, append(opts, grpc.Header(&header))...)
+	if vals := header.Get(grpctypes.GRPCBlockHeightHeader); len(vals) > 0 {
+		if h, perr := strconv.ParseInt(vals[0], 10, 64); perr == nil {
+			c.last.Store(h)
+		}
+	}
+	return err
+}
+
+// NewStream implements grpc.ClientConnInterface. Stream heights are not recorded.
+func (c *HeightConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.conn.NewStream(c.pin(ctx), desc, method, opts...)
+}
+
+// Height returns the height calls are pinned to, or 0 for the latest block.
+func (c *HeightConn) Height() int64 {
+	return c.height
+}
+
+// LastHeight returns the block height the node reported for the most recent call, or 0 before any call
+// has reported one.
+func (c *HeightConn) LastHeight() int64 {
+	return c.last.Load()
+}
+
+// AtHeight returns a read-only view of the client whose queries are all answered as of block height h,
+// including those of the lazy query clients and of anything built on the view's Grpc. Use it to
+// reconstruct state at a past height; the node must not have pruned it. The view has no Signer, so
+// it cannot sign or broadcast. Closing the view does not close the client.
+func (c *ProvenanceClient) AtHeight(h int64) *ProvenanceClient {
+	hc := NewHeightConn(c.Grpc, h)
+	v := c.view()
+	v.Grpc = c.Grpc.view(hc)
+	v.Address = c.Address
+	v.AccountNumber = c.AccountNumber
+	v.heights = hc
+	return v
+}
+
+// QueryHeight returns the block height the node reported for the most recent query made through an
+// AtHeight view. It is 0 for clients that are not views and before the view's first query.
+func (c *ProvenanceClient) QueryHeight() int64 {
+	if c.heights == nil {
+		return 0
+	}
+	return c.heights.LastHeight()
+}
@@ -0,0 +1,65 @@
+package provenance
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	tmtypes "cosmossdk.io/api/cosmos/base/tendermint/v1beta1"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// heightNode answers as of the requested height, or 100 when none is given, like a node's gRPC server.
+type heightNode struct {
+	tmtypes.UnimplementedServiceServer
+}
+
+func (heightNode) GetNodeInfo(ctx context.Context, _ *tmtypes.GetNodeInfoRequest) (*tmtypes.GetNodeInfoResponse, error) {
+	height := "100"
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(grpctypes.GRPCBlockHeightHeader)) > 0 {
+		height = md.Get(grpctypes.GRPCBlockHeightHeader)[0]
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(grpctypes.GRPCBlockHeightHeader, height)); err != nil {
+		return nil, err
+	}
+	return &tmtypes.GetNodeInfoResponse{ApplicationVersion: &tmtypes.VersionInfo{Version: height}}, nil
+}
+
+func TestAtHeight(t *testing.T) {
+	t.Parallel()
+	conn := serveTestNode(t, func(srv *grpc.Server) {
+		tmtypes.RegisterServiceServer(srv, heightNode{})
+	})
+	c := &ProvenanceClient{Grpc: conn}
+
+	for _, h := range []int64{0, 42} {
+		view := c.AtHeight(h)
+		res, err := (*view.TendermintClient()).GetNodeInfo(context.Background(), &tmtypes.GetNodeInfoRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := h
+		if h == 0 {
+			want = 100
+		}
+		if res.ApplicationVersion.Version != strconv.FormatInt(want, 10) || view.QueryHeight() != want {
+			t.Fatalf("AtHeight(%d): answered at %s, QueryHeight %d", h, res.ApplicationVersion.Version, view.QueryHeight())
+		}
+		// Health and status are the client's.
+		view.Grpc.CheckHealth(context.Background())
+		if got := view.Grpc.Status(); len(got) != 1 || got[0].URI != conn.Conn.Target() {
+			t.Fatalf("AtHeight(%d): unexpected status %+v", h, got)
+		}
+		view.Close()
+	}
+
+	// Closing the views leaves the client's connection open.
+	if _, err := (*c.TendermintClient()).GetNodeInfo(context.Background(), &tmtypes.GetNodeInfoRequest{}); err != nil {
+		t.Fatalf("client query after closing views: %v", err)
+	}
+	if c.QueryHeight() != 0 {
+		t.Fatalf("client QueryHeight = %d", c.QueryHeight())
+	}
+}
@@ -2,28 +2,104 @@ package provenance
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"strings"
 
+	addresscodec "github.com/cosmos/cosmos-sdk/codec/address"
 	hd "github.com/cosmos/cosmos-sdk/crypto/hd"
 	cryptokey "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/cosmos/go-bip39"
 )
 
+// Derivation selects a key derived from a mnemonic: the one at m/44'/coin'/Account'/0/Index, with
+// Passphrase as the BIP39 passphrase. The zero value is the first key with no passphrase, which is
+// what `provenanced keys add --recover` derives by default.
+type Derivation struct {
+	Account    uint32
+	Index      uint32
+	Passphrase string
+}
+
+// GenerateMnemonic returns a new BIP39 mnemonic with bits of entropy: 128 for 12 words or 256 for 24.
+func GenerateMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", fmt.Errorf("error generating entropy: %w", err)
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
 func PrivKeyFromMnemonic(conf BlockchainConfigProvider, mnemonic string) (*cryptokey.PrivKey, error) {
-	algo := hd.Secp256k1
-	hdPath := hd.CreateHDPath(conf.CoinType(), 0, 0).String()
+	return DerivePrivKey(conf, mnemonic, Derivation{})
+}
+
+const (
+	// maxDeriveAddresses bounds how many addresses DeriveAddresses returns in one call.
+	maxDeriveAddresses = 10000
+	// maxAddressIndex is the last unhardened BIP32 index, the highest a Derivation's Index can be.
+	maxAddressIndex = math.MaxInt32
+)
+
+// DerivePrivKey derives the key d selects from mnemonic for the configured coin type.
+func DerivePrivKey(conf BlockchainConfigProvider, mnemonic string, d Derivation) (*cryptokey.PrivKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, d.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return derivePrivKeyFromSeed(conf, seed, d.Account, d.Index)
+}
+
+// derivePrivKeyFromSeed derives the key at m/44'/coin'/account'/0/index from a BIP39 seed, so callers
+// deriving many keys only stretch the mnemonic once.
+func derivePrivKeyFromSeed(conf BlockchainConfigProvider, seed []byte, account, index uint32) (*cryptokey.PrivKey, error) {
+	hdPath := hd.CreateHDPath(conf.CoinType(), account, index).String()
 
-	derivedPriv, err := algo.Derive()(mnemonic, "", hdPath)
+	master, chainCode := hd.ComputeMastersFromSeed(seed)
+	derivedPriv, err := hd.DerivePrivateKeyForPath(master, chainCode, hdPath)
 	if err != nil {
 		return nil, err
 	}
 
-	priv := algo.Generate()(derivedPriv)
+	priv := hd.Secp256k1.Generate()(derivedPriv)
 	return priv.(*cryptokey.PrivKey), nil
 }
 
+// DeriveAddresses returns the addresses of the n keys starting at d, at indices d.Index through
+// d.Index+n-1 of d.Account, encoded with the configured prefix. n is at most 10000 and the last index
+// at most 2^31-1.
+func DeriveAddresses(conf BlockchainConfigProvider, mnemonic string, d Derivation, n int) ([]string, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("number of addresses must not be negative, got %d", n)
+	}
+	if n > maxDeriveAddresses {
+		return nil, fmt.Errorf("number of addresses must be at most %d, got %d", maxDeriveAddresses, n)
+	}
+	if n > 0 && uint64(d.Index)+uint64(n-1) > maxAddressIndex {
+		return nil, fmt.Errorf("%d addresses from index %d run past the last index %d", n, d.Index, maxAddressIndex)
+	}
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, d.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	ac := addresscodec.NewBech32Codec(conf.AddressPrefix())
+	addrs := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		priv, err := derivePrivKeyFromSeed(conf, seed, d.Account, d.Index+uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		addr, err := ac.BytesToString(sdk.AccAddress(priv.PubKey().Address()))
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
 func ReadMnemonic(path string) (*string, error) {
 	mnemonic, err := os.ReadFile(path)
 	if err != nil {
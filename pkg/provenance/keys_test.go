@@ -0,0 +1,79 @@
+package provenance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cosmos/go-bip39"
+)
+
+func TestGenerateMnemonic(t *testing.T) {
+	t.Parallel()
+	for bits, words := range map[int]int{128: 12, 256: 24} {
+		m, err := GenerateMnemonic(bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(strings.Fields(m)) != words || !bip39.IsMnemonicValid(m) {
+			t.Fatalf("%d bits: %q", bits, m)
+		}
+	}
+	if _, err := GenerateMnemonic(100); err == nil {
+		t.Fatal("expected an error for invalid entropy size")
+	}
+}
+
+func TestDerivation(t *testing.T) {
+	t.Parallel()
+	conf := NewTestnetConfig()
+
+	first, err := DerivePrivKey(conf, offlineTestMnemonic, Derivation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := PrivKeyFromMnemonic(conf, offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Equals(legacy) {
+		t.Fatal("zero Derivation differs from PrivKeyFromMnemonic")
+	}
+
+	addrs, err := DeriveAddresses(conf, offlineTestMnemonic, Derivation{Account: 1, Index: 5}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for i, addr := range addrs {
+		s, err := NewDerivedSigner(conf, offlineTestMnemonic, Derivation{Account: 1, Index: 5 + uint32(i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := (&ProvenanceClient{BcConfig: conf}).FormatAddress(s.Address())
+		if addr != want || !strings.HasPrefix(addr, conf.AddressPrefix()) || seen[addr] {
+			t.Fatalf("address %d: %s, want %s", i, addr, want)
+		}
+		seen[addr] = true
+	}
+	if _, err := DeriveAddresses(conf, offlineTestMnemonic, Derivation{}, -1); err == nil {
+		t.Fatal("expected an error for a negative count")
+	}
+	if _, err := DeriveAddresses(conf, offlineTestMnemonic, Derivation{}, maxDeriveAddresses+1); err == nil {
+		t.Fatal("expected an error for a count above the limit")
+	}
+	if _, err := DeriveAddresses(conf, offlineTestMnemonic, Derivation{Index: maxAddressIndex - 1}, 3); err == nil {
+		t.Fatal("expected an error for indices past the last one")
+	}
+	last, err := DeriveAddresses(conf, offlineTestMnemonic, Derivation{Index: maxAddressIndex - 1}, 2)
+	if err != nil || len(last) != 2 {
+		t.Fatalf("got %v, %v want the last 2 addresses", last, err)
+	}
+
+	withPassphrase, err := DerivePrivKey(conf, offlineTestMnemonic, Derivation{Passphrase: "extra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if withPassphrase.Equals(first) {
+		t.Fatal("passphrase did not change the derived key")
+	}
+}
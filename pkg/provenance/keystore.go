@@ -0,0 +1,190 @@
+package provenance
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KeystoreOptions sets the scrypt cost of encrypting a keystore. Zero fields fall back to
+// DefaultKeystoreOptions; decryption reads the cost from the keystore itself.
+type KeystoreOptions struct {
+	// ScryptN is the CPU/memory cost, a power of two. Memory use is 128*N*R bytes.
+	ScryptN int
+	ScryptR int
+	ScryptP int
+}
+
+// DefaultKeystoreOptions takes about a second and 256 MiB to derive the key on a current machine.
+var DefaultKeystoreOptions = KeystoreOptions{
+	ScryptN: 1 << 18,
+	ScryptR: 8,
+	ScryptP: 1,
+}
+
+// Keystores asking for more scrypt memory or parallelism than this are refused, so that a crafted or
+// corrupt file cannot make DecryptKey exhaust the machine. The memory limit is four times that of
+// DefaultKeystoreOptions.
+const (
+	maxKeystoreMemory  = 1 << 30
+	maxKeystoreScryptP = 16
+)
+
+// validate checks the scrypt cost against the limits above.
+func (o KeystoreOptions) validate() error {
+	if o.ScryptN <= 1 || o.ScryptN&(o.ScryptN-1) != 0 {
+		return fmt.Errorf("keystore scrypt N must be a power of two greater than 1, got %d", o.ScryptN)
+	}
+	if o.ScryptR < 1 || o.ScryptP < 1 || o.ScryptP > maxKeystoreScryptP {
+		return fmt.Errorf("keystore scrypt r and p must be positive and p at most %d, got r=%d p=%d", maxKeystoreScryptP, o.ScryptR, o.ScryptP)
+	}
+	if o.ScryptN > maxKeystoreMemory/128/o.ScryptR {
+		return fmt.Errorf("keystore scrypt N=%d r=%d needs more than %d MiB", o.ScryptN, o.ScryptR, maxKeystoreMemory>>20)
+	}
+	return nil
+}
+
+func (o KeystoreOptions) withDefaults() KeystoreOptions {
+	if o.ScryptN == 0 {
+		o.ScryptN = DefaultKeystoreOptions.ScryptN
+	}
+	if o.ScryptR == 0 {
+		o.ScryptR = DefaultKeystoreOptions.ScryptR
+	}
+	if o.ScryptP == 0 {
+		o.ScryptP = DefaultKeystoreOptions.ScryptP
+	}
+	return o
+}
+
+const keystoreVersion = 1
+
+// keystoreFile is the on-disk keystore: a secp256k1 private key sealed with AES-256-GCM under a key
+// derived from the password with scrypt. The public key is kept in the clear, and authenticated, so
+// the account can be identified without the password.
+type keystoreFile struct {
+	Version    int    `json:"version"`
+	PubKey     []byte `json:"pub_key"`
+	KDF        string `json:"kdf"`
+	ScryptN    int    `json:"scrypt_n"`
+	ScryptR    int    `json:"scrypt_r"`
+	ScryptP    int    `json:"scrypt_p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptKey seals priv with password in the keystore format read by DecryptKey.
+func EncryptKey(priv *secp256k1.PrivKey, password string, opts KeystoreOptions) ([]byte, error) {
+	if password == "" {
+		return nil, fmt.Errorf("keystore password is required")
+	}
+	opts = opts.withDefaults()
+
+	ks := keystoreFile{
+		Version: keystoreVersion,
+		PubKey:  priv.PubKey().Bytes(),
+		KDF:     "scrypt",
+		ScryptN: opts.ScryptN,
+		ScryptR: opts.ScryptR,
+		ScryptP: opts.ScryptP,
+		Salt:    make([]byte, 32),
+	}
+	if _, err := rand.Read(ks.Salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := ks.aead(password)
+	if err != nil {
+		return nil, err
+	}
+	ks.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(ks.Nonce); err != nil {
+		return nil, err
+	}
+	ks.Ciphertext = aead.Seal(nil, ks.Nonce, priv.Key, ks.PubKey)
+
+	return json.MarshalIndent(ks, "", "  ")
+}
+
+// DecryptKey opens a keystore made by EncryptKey.
+func DecryptKey(data []byte, password string) (*secp256k1.PrivKey, error) {
+	var ks keystoreFile
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("error decoding keystore: %w", err)
+	}
+	if ks.Version != keystoreVersion || ks.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported keystore version %d with kdf %q", ks.Version, ks.KDF)
+	}
+
+	aead, err := ks.aead(password)
+	if err != nil {
+		return nil, err
+	}
+	key, err := aead.Open(nil, ks.Nonce, ks.Ciphertext, ks.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrong keystore password or corrupt keystore")
+	}
+
+	priv := &secp256k1.PrivKey{Key: key}
+	if !priv.PubKey().Equals(&secp256k1.PubKey{Key: ks.PubKey}) {
+		return nil, fmt.Errorf("keystore key does not match its public key")
+	}
+	return priv, nil
+}
+
+func (ks keystoreFile) aead(password string) (cipher.AEAD, error) {
+	cost := KeystoreOptions{ScryptN: ks.ScryptN, ScryptR: ks.ScryptR, ScryptP: ks.ScryptP}
+	if err := cost.validate(); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key([]byte(password), ks.Salt, ks.ScryptN, ks.ScryptR, ks.ScryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving keystore key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// WriteKeystore encrypts priv with EncryptKey and writes it to path, readable by the owner only.
+func WriteKeystore(path string, priv *secp256k1.PrivKey, password string, opts KeystoreOptions) error {
+	data, err := EncryptKey(priv, password, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing keystore: %w", err)
+	}
+	return nil
+}
+
+// NewKeystoreSigner opens the keystore at path with password.
+func NewKeystoreSigner(path, password string) (*MnemonicSigner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading keystore: %w", err)
+	}
+	priv, err := DecryptKey(data, password)
+	if err != nil {
+		return nil, err
+	}
+	return &MnemonicSigner{priv: priv}, nil
+}
+
+// WithKeystore signs with the key in the keystore at path, opened with password when the client is
+// created.
+func WithKeystore(path, password string) ClientOption {
+	return func(c *ProvenanceClient) {
+		c.keystorePath = path
+		c.keystorePassword = password
+	}
+}
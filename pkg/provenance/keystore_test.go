@@ -0,0 +1,77 @@
+package provenance
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testKeystoreOptions keeps scrypt cheap so the tests run quickly.
+var testKeystoreOptions = KeystoreOptions{ScryptN: 1 << 10}
+
+func TestKeystore(t *testing.T) {
+	t.Parallel()
+	priv, err := DerivePrivKey(NewTestnetConfig(), offlineTestMnemonic, Derivation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := WriteKeystore(path, priv, "hunter2", testKeystoreOptions); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte(base64.StdEncoding.EncodeToString(priv.Key))) {
+		t.Fatal("keystore holds the key in the clear")
+	}
+
+	signer, err := NewKeystoreSigner(path, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signer.PubKey().Equals(priv.PubKey()) {
+		t.Fatal("keystore signer has a different key")
+	}
+
+	if _, err := DecryptKey(data, "hunter3"); err == nil {
+		t.Fatal("expected an error for a wrong password")
+	}
+
+	// The public key is authenticated along with the private key.
+	var ks keystoreFile
+	if err := json.Unmarshal(data, &ks); err != nil {
+		t.Fatal(err)
+	}
+	ks.PubKey[1] ^= 1
+	tampered, _ := json.Marshal(ks)
+	if _, err := DecryptKey(tampered, "hunter2"); err == nil {
+		t.Fatal("expected an error for a tampered public key")
+	}
+
+	// Costs that would take too much memory, or that scrypt rejects, are refused before deriving.
+	for _, cost := range []KeystoreOptions{
+		{ScryptN: 1 << 30, ScryptR: 8, ScryptP: 1},
+		{ScryptN: 1 << 10, ScryptR: 1 << 20, ScryptP: 1},
+		{ScryptN: 1000, ScryptR: 8, ScryptP: 1},
+		{ScryptN: 1 << 10, ScryptR: 8, ScryptP: 1 << 20},
+		{ScryptN: 1 << 10, ScryptR: -1, ScryptP: 1},
+	} {
+		if err := json.Unmarshal(data, &ks); err != nil {
+			t.Fatal(err)
+		}
+		ks.ScryptN, ks.ScryptR, ks.ScryptP = cost.ScryptN, cost.ScryptR, cost.ScryptP
+		crafted, _ := json.Marshal(ks)
+		if _, err := DecryptKey(crafted, "hunter2"); err == nil {
+			t.Fatalf("expected an error for scrypt cost %+v", cost)
+		}
+	}
+
+	if _, err := EncryptKey(priv, "", testKeystoreOptions); err == nil {
+		t.Fatal("expected an error for an empty password")
+	}
+}
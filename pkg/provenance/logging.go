@@ -0,0 +1,51 @@
+package provenance
+
+import (
+	"log/slog"
+)
+
+// WithLogger sends the client's signing, broadcast and wait events to l. Without it nothing is logged.
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *ProvenanceClient) {
+		c.Logger = l
+	}
+}
+
+// Progress receives progress updates from long-running operations such as AddAttributes.
+type Progress interface {
+	// IncrementCount marks one more item as processed.
+	IncrementCount()
+	// SetCurrent describes the step currently in progress.
+	SetCurrent(msg string)
+}
+
+// Verify that the Renderer implements the Progress interface
+var _ Progress = (*Renderer)(nil)
+
+// WithProgress reports the progress of long-running operations to p, e.g. a terminal Renderer.
+func WithProgress(p Progress) ClientOption {
+	return func(c *ProvenanceClient) {
+		c.Progress = p
+	}
+}
+
+// logger returns the client's Logger, or a logger that discards everything when none is set.
+func (c *ProvenanceClient) logger() *slog.Logger {
+	if c.Logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return c.Logger
+}
+
+// progress returns the client's Progress, or one that ignores updates when none is set.
+func (c *ProvenanceClient) progress() Progress {
+	if c.Progress == nil {
+		return noProgress{}
+	}
+	return c.Progress
+}
+
+type noProgress struct{}
+
+func (noProgress) IncrementCount()   {}
+func (noProgress) SetCurrent(string) {}
@@ -0,0 +1,42 @@
+package provenance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestLoggerRecordsWait(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	c := unreachableClient(t)
+	WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.WaitOnTx(ctx, "ABCD", WithPollInterval(10*time.Millisecond)); err == nil {
+		t.Fatal("expected wait to time out")
+	}
+
+	var msgs []string
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var rec struct {
+			Msg    string `json:"msg"`
+			TxHash string `json:"tx_hash"`
+		}
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatalf("decode log record: %v", err)
+		}
+		if rec.TxHash != "ABCD" {
+			t.Fatalf("record %q missing tx_hash", rec.Msg)
+		}
+		msgs = append(msgs, rec.Msg)
+	}
+	if len(msgs) != 2 || msgs[0] != "waiting on tx" || msgs[1] != "gave up waiting on tx" {
+		t.Fatalf("unexpected records: %q", msgs)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx"
 	marker "github.com/provenance-io/provenance/x/marker/types"
 	metadata "github.com/provenance-io/provenance/x/metadata/keeper"
 	"github.com/provenance-io/provenance/x/metadata/types"
@@ -31,8 +32,16 @@ type MarkerValueResult struct {
 	Error    error
 }
 
+// GetMarker is GetMarkerContext with a background context.
+//
+// Deprecated: use GetMarkerContext.
 func (c *ProvenanceClient) GetMarker(denomOrAddress string) (*marker.MarkerAccountI, error) {
-	res, err := (*c.MarkerClient()).Marker(context.Background(), &marker.QueryMarkerRequest{
+	return c.GetMarkerContext(context.Background(), denomOrAddress)
+}
+
+// GetMarkerContext returns the marker account for a denom or marker address.
+func (c *ProvenanceClient) GetMarkerContext(ctx context.Context, denomOrAddress string) (*marker.MarkerAccountI, error) {
+	res, err := (*c.MarkerClient()).Marker(ctx, &marker.QueryMarkerRequest{
 		Id: denomOrAddress,
 	})
 
@@ -50,23 +59,125 @@ func (c *ProvenanceClient) GetMarker(denomOrAddress string) (*marker.MarkerAccou
 	return &acct, nil
 }
 
+// GetMarkerAddress is GetMarkerAddressContext with a background context.
+//
+// Deprecated: use GetMarkerAddressContext.
 func (c *ProvenanceClient) GetMarkerAddress(denom string) (*string, error) {
-	acct, err := c.GetMarker(denom)
+	return c.GetMarkerAddressContext(context.Background(), denom)
+}
+
+// GetMarkerAddressContext returns the address of the marker for denom.
+func (c *ProvenanceClient) GetMarkerAddressContext(ctx context.Context, denom string) (*string, error) {
+	acct, err := c.GetMarkerContext(ctx, denom)
 	if acct == nil || err != nil {
 		return nil, fmt.Errorf("no cached value for %s", denom)
 	}
 
-	// Get the address of the marker account that we'll use to query the balances
-	markerAddress := (*acct).GetAddress().String()
+	// Get the address of the marker account that we'll use to query the balances. GetAddress()
+	// re-encodes with the global SDK prefix, so use the address as the chain returned it.
+	ma, ok := (*acct).(*marker.MarkerAccount)
+	if !ok {
+		return nil, fmt.Errorf("unexpected marker account type %T for %s", *acct, denom)
+	}
+	markerAddress := ma.Address
 	return &markerAddress, nil
 }
 
+// AddMarker proposes the marker described by spec, with the client's address as its creator and,
+// unless spec.Manager is set, its manager. The marker holds no supply until FinalizeMarker and
+// ActivateMarker are sent.
+func (c *ProvenanceClient) AddMarker(ctx context.Context, spec MarkerSpec, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	if err := c.checkMarkerSpec(spec, false); err != nil {
+		return nil, fmt.Errorf("invalid marker %s: %w", spec.Amount.Denom, err)
+	}
+	return c.SignAndBroadcast(ctx, []sdk.Msg{NewAddMarker(c.Address, spec)}, opts...)
+}
+
+// AddFinalizeActivateMarker creates the marker described by spec and activates it in a single
+// transaction, minting its supply. spec.AccessList must grant at least one permission.
+func (c *ProvenanceClient) AddFinalizeActivateMarker(ctx context.Context, spec MarkerSpec, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	if err := c.checkMarkerSpec(spec, true); err != nil {
+		return nil, fmt.Errorf("invalid marker %s: %w", spec.Amount.Denom, err)
+	}
+	return c.SignAndBroadcast(ctx, []sdk.Msg{NewAddFinalizeActivateMarker(c.Address, spec)}, opts...)
+}
+
+// checkMarkerSpec applies the marker module's basic checks to spec. The module's own ValidateBasic
+// parses addresses with the global SDK prefix, so it cannot be used.
+func (c *ProvenanceClient) checkMarkerSpec(spec MarkerSpec, activate bool) error {
+	if err := spec.Amount.Validate(); err != nil {
+		return err
+	}
+	if spec.Manager != "" {
+		if _, err := c.ParseAddress(spec.Manager); err != nil {
+			return fmt.Errorf("manager: %w", err)
+		}
+	}
+	if activate && len(spec.AccessList) == 0 {
+		return fmt.Errorf("an activated marker needs an access list")
+	}
+	for _, grant := range spec.AccessList {
+		if _, err := c.ParseAddress(grant.Address); err != nil {
+			return fmt.Errorf("access grant: %w", err)
+		}
+	}
+	if !spec.Restricted && spec.AllowForcedTransfer {
+		return fmt.Errorf("forced transfer is only available for restricted markers")
+	}
+	if !spec.Restricted && len(spec.RequiredAttributes) > 0 {
+		return fmt.Errorf("required attributes are only available for restricted markers")
+	}
+	seen := map[string]bool{}
+	for _, attr := range spec.RequiredAttributes {
+		if seen[attr] {
+			return fmt.Errorf("required attribute %q is listed twice", attr)
+		}
+		seen[attr] = true
+	}
+	return nil
+}
+
+// FinalizeMarker finalizes a proposed marker, fixing its configuration. The client's address must be
+// the marker's manager.
+func (c *ProvenanceClient) FinalizeMarker(ctx context.Context, denom string, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	return c.SignAndBroadcast(ctx, []sdk.Msg{NewFinalizeMarker(c.Address, denom)}, opts...)
+}
+
+// ActivateMarker activates a finalized marker, minting its supply and applying its access list.
+func (c *ProvenanceClient) ActivateMarker(ctx context.Context, denom string, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	return c.SignAndBroadcast(ctx, []sdk.Msg{NewActivateMarker(c.Address, denom)}, opts...)
+}
+
+// CancelMarker cancels a marker so it can be deleted. The client's address needs delete access, or
+// must be the manager of a marker that is not yet active.
+func (c *ProvenanceClient) CancelMarker(ctx context.Context, denom string, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	return c.SignAndBroadcast(ctx, []sdk.Msg{NewCancelMarker(c.Address, denom)}, opts...)
+}
+
+// DeleteMarker destroys a cancelled marker and burns the supply it holds. The client's address needs
+// delete access.
+func (c *ProvenanceClient) DeleteMarker(ctx context.Context, denom string, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	return c.SignAndBroadcast(ctx, []sdk.Msg{NewDeleteMarker(c.Address, denom)}, opts...)
+}
+
 // GetAccountValue gets the value of an account by address/denom
 func (c *ProvenanceClient) GetAccountValue(ctx context.Context, addressOrDenom string) (*AccountValue, error) {
+	// Stops the balance stream and any NAV queries still running on every return path
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// WaitGroup and Channels to handle results from the NAV queries
 	wg := sync.WaitGroup{}
 	totalChan := make(chan *AccountValue, 1)
 	resultsChan := make(chan *NFTAccount, 1)
+	// The first error from a NAV query; we don't want to report any results if there is one
+	workErrChan := make(chan error, 1)
+	fail := func(err error) {
+		select {
+		case workErrChan <- err:
+		default:
+		}
+	}
 
 	// total routine to sum up the results from the results channel
 	go func() {
@@ -103,6 +214,12 @@ func (c *ProvenanceClient) GetAccountValue(ctx context.Context, addressOrDenom s
 				wg.Wait()
 				close(resultsChan)
 
+				select {
+				case err := <-workErrChan:
+					return nil, err
+				default:
+				}
+
 				// Get the total from the total channel
 				total := <-totalChan
 				return total, nil
@@ -115,14 +232,14 @@ func (c *ProvenanceClient) GetAccountValue(ctx context.Context, addressOrDenom s
 					scopeId := parts[1]
 
 					wg.Add(1)
-					c.Pool.Submit(func() {
+					err := c.Pool.Submit(func() {
 						defer wg.Done()
 
 						var nav *types.NetAssetValue
-						nav, err := c.GetNAV(scopeId)
+						nav, err := c.GetNAVContext(ctx, scopeId)
 						if err != nil {
-							// Panic here since we don't want to report any results if there is an error
-							panic(err)
+							fail(fmt.Errorf("error getting NAV for %s: %w", scopeId, err))
+							return
 						}
 
 						// No NAV? Just exit...
@@ -135,12 +252,14 @@ func (c *ProvenanceClient) GetAccountValue(ctx context.Context, addressOrDenom s
 
 						metadataAddress, err := metadata.ParseScopeID(scopeId)
 						if err != nil {
-							panic(err)
+							fail(fmt.Errorf("error parsing scope id %s: %w", scopeId, err))
+							return
 						}
 
 						uuid, err := metadataAddress.ScopeUUID()
 						if err != nil {
-							panic(err)
+							fail(fmt.Errorf("error parsing scope id %s: %w", scopeId, err))
+							return
 						}
 
 						nftAccount := NFTAccount{
@@ -154,9 +273,23 @@ func (c *ProvenanceClient) GetAccountValue(ctx context.Context, addressOrDenom s
 							resultsChan <- &nftAccount
 						}
 					})
+					if err != nil {
+						wg.Done()
+						fail(fmt.Errorf("error submitting NAV query for %s: %w", scopeId, err))
+					}
 				}
 			}
-		case err := <-errChan:
+		case err := <-workErrChan:
+			// Error from a NAV query - wait for existing work and return error
+			wg.Wait()
+			close(resultsChan)
+			return nil, err
+		case err, ok := <-errChan:
+			if !ok {
+				// Closed along with balancesChan once every balance has been sent
+				errChan = nil
+				continue
+			}
 			// Error from stream - wait for existing work and return error
 			wg.Wait()
 			close(resultsChan)
@@ -0,0 +1,166 @@
+package provenance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/google/uuid"
+	"github.com/panjf2000/ants/v2"
+	marker "github.com/provenance-io/provenance/x/marker/types"
+	meta "github.com/provenance-io/provenance/x/metadata/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMarkerMessages(t *testing.T) {
+	t.Parallel()
+	addrs, err := DeriveAddresses(NewLocalnetConfig(), offlineTestMnemonic, Derivation{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := addrs[0]
+	spec := MarkerSpec{
+		Amount:              sdk.NewInt64Coin("fund.one", 1_000_000),
+		Restricted:          true,
+		SupplyFixed:         true,
+		AllowForcedTransfer: true,
+		AccessList: []marker.AccessGrant{
+			NewMarkerAccessGrant(signer, marker.Access_Admin, marker.Access_Mint, marker.Access_Transfer, marker.Access_Delete),
+		},
+	}
+
+	add := NewAddMarker(signer, spec)
+	if add.Status != marker.StatusProposed || add.Manager != signer || add.MarkerType != marker.MarkerType_RestrictedCoin {
+		t.Fatalf("add marker: %v", add)
+	}
+
+	afa := NewAddFinalizeActivateMarker(signer, spec)
+	if afa.Manager != signer || !afa.SupplyFixed || len(afa.AccessList) != 1 {
+		t.Fatalf("add finalize activate marker: %v", afa)
+	}
+
+	if m := NewCancelMarker(signer, "fund.one"); m.Administrator != signer || m.Denom != "fund.one" {
+		t.Fatalf("cancel marker: %v", m)
+	}
+
+	// Invalid specs are refused before anything is signed.
+	c := &ProvenanceClient{BcConfig: NewLocalnetConfig(), Address: signer}
+	if err := c.checkMarkerSpec(spec, true); err != nil {
+		t.Fatal(err)
+	}
+	coin := spec
+	coin.Restricted = false
+	if _, err := c.AddFinalizeActivateMarker(context.Background(), coin); err == nil {
+		t.Fatal("expected forced transfer on a coin marker to be refused")
+	}
+	noAccess := spec
+	noAccess.AccessList = nil
+	if _, err := c.AddFinalizeActivateMarker(context.Background(), noAccess); err == nil {
+		t.Fatal("expected an empty access list to be refused")
+	}
+	if err := c.checkMarkerSpec(noAccess, false); err != nil {
+		t.Fatalf("proposed marker without access list: %v", err)
+	}
+}
+
+// navNode holds one NFT per scope and answers NAV queries with err, or a usd price when err is nil.
+// With hang set, the balances claim a second page whose query closes paging, blocks until it is
+// canceled and then closes hang; NAV queries wait for paging.
+type navNode struct {
+	*banktypes.UnimplementedQueryServer
+	scopes []string
+	err    error
+	paging chan struct{}
+	hang   chan struct{}
+}
+
+func (n navNode) AllBalances(ctx context.Context, req *banktypes.QueryAllBalancesRequest) (*banktypes.QueryAllBalancesResponse, error) {
+	res := &banktypes.QueryAllBalancesResponse{Pagination: &query.PageResponse{}}
+	if n.hang != nil {
+		if len(req.Pagination.GetKey()) > 0 {
+			close(n.paging)
+			<-ctx.Done()
+			close(n.hang)
+			return nil, ctx.Err()
+		}
+		res.Pagination.NextKey = []byte("next")
+	}
+	for _, scope := range n.scopes {
+		res.Balances = append(res.Balances, sdk.NewInt64Coin("nft/"+scope, 1))
+	}
+	return res, nil
+}
+
+type navMetadata struct {
+	*meta.UnimplementedQueryServer
+	node navNode
+}
+
+func (m navMetadata) ScopeNetAssetValues(context.Context, *meta.QueryScopeNetAssetValuesRequest) (*meta.QueryScopeNetAssetValuesResponse, error) {
+	if m.node.paging != nil {
+		<-m.node.paging
+	}
+	if m.node.err != nil {
+		return nil, m.node.err
+	}
+	return &meta.QueryScopeNetAssetValuesResponse{NetAssetValues: []meta.NetAssetValue{{Price: sdk.NewInt64Coin("usd", 7)}}}, nil
+}
+
+func TestGetAccountValue(t *testing.T) {
+	t.Parallel()
+	scopes := []string{
+		meta.ScopeMetadataAddress(uuid.New()).String(),
+		meta.ScopeMetadataAddress(uuid.New()).String(),
+	}
+	pool, _ := ants.NewPool(2)
+	t.Cleanup(pool.Release)
+
+	for _, tc := range []struct {
+		name  string
+		err   error
+		total int64
+	}{
+		{name: "ok", total: 14},
+		{name: "nav error", err: status.Error(codes.DeadlineExceeded, "too slow")},
+	} {
+		node := navNode{scopes: scopes, err: tc.err}
+		conn := serveTestNode(t, func(srv *grpc.Server) {
+			banktypes.RegisterQueryServer(srv, node)
+			meta.RegisterQueryServer(srv, navMetadata{node: node})
+		})
+		c := &ProvenanceClient{Grpc: conn, Pool: pool}
+
+		value, err := c.GetAccountValue(context.Background(), "tp1owner")
+		if tc.err != nil {
+			// The failed NAV query is reported instead of being left out of the total.
+			if status.Code(err) != codes.DeadlineExceeded || value != nil {
+				t.Fatalf("%s: got %v, %v", tc.name, value, err)
+			}
+			continue
+		}
+		if err != nil || value.Total.Amount.Int64() != tc.total || len(value.NFTs) != len(scopes) {
+			t.Fatalf("%s: got %+v, %v", tc.name, value, err)
+		}
+	}
+
+	// A failed NAV query also stops the balance query still in flight.
+	node := navNode{scopes: scopes, err: status.Error(codes.NotFound, "no nav"), paging: make(chan struct{}), hang: make(chan struct{})}
+	conn := serveTestNode(t, func(srv *grpc.Server) {
+		banktypes.RegisterQueryServer(srv, node)
+		meta.RegisterQueryServer(srv, navMetadata{node: node})
+	})
+	c := &ProvenanceClient{Grpc: conn, Pool: pool}
+	if _, err := c.GetAccountValue(context.Background(), "tp1owner"); status.Code(err) != codes.NotFound {
+		t.Fatalf("got %v want %v", err, codes.NotFound)
+	}
+	select {
+	case <-node.hang:
+	case <-time.After(5 * time.Second):
+		t.Fatal("balance query was not canceled")
+	}
+}
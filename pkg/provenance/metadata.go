@@ -10,49 +10,62 @@ import (
 	meta "github.com/provenance-io/provenance/x/metadata/types"
 )
 
-func (c *ProvenanceClient) CreateScope(scopeSpecUUID, scopeUUID string) (*tx.BroadcastTxResponse, error) {
+// CreateScope is CreateScopeContext with a background context.
+//
+// Deprecated: use CreateScopeContext.
+func (c *ProvenanceClient) CreateScope(scopeSpecUUID, scopeUUID string, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	return c.CreateScopeContext(context.Background(), scopeSpecUUID, scopeUUID, opts...)
+}
+
+// CreateScopeContext writes a new scope owned by the client's address.
+func (c *ProvenanceClient) CreateScopeContext(ctx context.Context, scopeSpecUUID, scopeUUID string, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
 	// Verify that the scope doesn't already exist
-	scope, err := c.GetScope(scopeUUID)
+	scope, err := c.GetScopeContext(ctx, scopeUUID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting scope: %w", err)
 	}
 	if scope != nil {
-		return nil, fmt.Errorf("scope already exists")
+		return nil, fmt.Errorf("scope %s: %w", scopeUUID, ErrAlreadyExists)
 	}
 
 	msg := NewScope(c.Address, scopeSpecUUID, scopeUUID)
 
-	txBz, err := c.SignTx([]sdk.Msg{msg}, c.PrivKey.Bytes(), c.AccountNumber, c.NextSequence(), 0)
-	if err != nil {
-		return nil, fmt.Errorf("error creating tx: %w", err)
-	}
-
-	resp, err := c.BroadcastTx(txBz)
+	resp, err := c.SignAndBroadcast(ctx, []sdk.Msg{msg}, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("error broadcasting transaction: %w", err)
+		return nil, err
 	}
 
 	return resp, nil
 }
 
+// DeleteScope is DeleteScopeContext with a background context.
+//
+// Deprecated: use DeleteScopeContext.
+func (c *ProvenanceClient) DeleteScope(scopeUuid string, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	return c.DeleteScopeContext(context.Background(), scopeUuid, opts...)
+}
+
 // Delete a scope
-func (c *ProvenanceClient) DeleteScope(scopeUuid string) (*tx.BroadcastTxResponse, error) {
+func (c *ProvenanceClient) DeleteScopeContext(ctx context.Context, scopeUuid string, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
 	msg := NewDeleteScope(c.Address, scopeUuid)
 
-	txBz, err := c.SignTx([]sdk.Msg{msg}, c.PrivKey.Bytes(), c.AccountNumber, c.NextSequence(), 0)
+	resp, err := c.SignAndBroadcast(ctx, []sdk.Msg{msg}, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("error creating tx: %w", err)
-	}
-
-	resp, err := c.BroadcastTx(txBz)
-	if err != nil {
-		return nil, fmt.Errorf("error broadcasting transaction: %w", err)
+		return nil, err
 	}
 
 	return resp, nil
 }
 
-func (c *ProvenanceClient) UpdateRecords(session *meta.MsgWriteSessionRequest, records []meta.MsgWriteRecordRequest) (*tx.BroadcastTxResponse, error) {
+// UpdateRecords is UpdateRecordsContext with a background context.
+//
+// Deprecated: use UpdateRecordsContext.
+func (c *ProvenanceClient) UpdateRecords(session *meta.MsgWriteSessionRequest, records []meta.MsgWriteRecordRequest, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	return c.UpdateRecordsContext(context.Background(), session, records, opts...)
+}
+
+// UpdateRecordsContext writes a session and its records in one transaction.
+func (c *ProvenanceClient) UpdateRecordsContext(ctx context.Context, session *meta.MsgWriteSessionRequest, records []meta.MsgWriteRecordRequest, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
 	if records == nil {
 		panic("records cannot be nil")
 	}
@@ -62,22 +75,24 @@ func (c *ProvenanceClient) UpdateRecords(session *meta.MsgWriteSessionRequest, r
 		msgs = append(msgs, &record)
 	}
 
-	txBz, err := c.SignTx(msgs, c.PrivKey.Bytes(), c.AccountNumber, c.NextSequence(), 0)
-	if err != nil {
-		return nil, fmt.Errorf("error creating tx: %w", err)
-	}
-
-	resp, err := c.BroadcastTx(txBz)
+	resp, err := c.SignAndBroadcast(ctx, msgs, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("error broadcasting transaction: %w", err)
+		return nil, err
 	}
 
 	return resp, nil
 }
 
-// Get the NAV for a given scope
+// GetNAV is GetNAVContext with a background context.
+//
+// Deprecated: use GetNAVContext.
 func (c *ProvenanceClient) GetNAV(scopeId string) (*meta.NetAssetValue, error) {
-	res, err := (*c.MetadataClient()).ScopeNetAssetValues(context.Background(), &meta.QueryScopeNetAssetValuesRequest{
+	return c.GetNAVContext(context.Background(), scopeId)
+}
+
+// Get the NAV for a given scope
+func (c *ProvenanceClient) GetNAVContext(ctx context.Context, scopeId string) (*meta.NetAssetValue, error) {
+	res, err := (*c.MetadataClient()).ScopeNetAssetValues(ctx, &meta.QueryScopeNetAssetValuesRequest{
 		Id: scopeId,
 	})
 	if err != nil {
@@ -93,8 +108,16 @@ func (c *ProvenanceClient) GetNAV(scopeId string) (*meta.NetAssetValue, error) {
 	return &nav, nil
 }
 
+// GetContractSpec is GetContractSpecContext with a background context.
+//
+// Deprecated: use GetContractSpecContext.
 func (c *ProvenanceClient) GetContractSpec(specId string) (*meta.ContractSpecification, error) {
-	res, err := (*c.MetadataClient()).ContractSpecification(context.Background(), &meta.ContractSpecificationRequest{
+	return c.GetContractSpecContext(context.Background(), specId)
+}
+
+// GetContractSpecContext returns a contract specification without its record specifications.
+func (c *ProvenanceClient) GetContractSpecContext(ctx context.Context, specId string) (*meta.ContractSpecification, error) {
+	res, err := (*c.MetadataClient()).ContractSpecification(ctx, &meta.ContractSpecificationRequest{
 		SpecificationId:    specId,
 		IncludeRecordSpecs: false,
 	})
@@ -105,8 +128,16 @@ func (c *ProvenanceClient) GetContractSpec(specId string) (*meta.ContractSpecifi
 	return res.ContractSpecification.Specification, nil
 }
 
+// GetScopeSpec is GetScopeSpecContext with a background context.
+//
+// Deprecated: use GetScopeSpecContext.
 func (c *ProvenanceClient) GetScopeSpec(specId string) (*meta.ScopeSpecification, error) {
-	res, err := (*c.MetadataClient()).ScopeSpecification(context.Background(), &meta.ScopeSpecificationRequest{
+	return c.GetScopeSpecContext(context.Background(), specId)
+}
+
+// GetScopeSpecContext returns a scope specification without its contract or record specifications.
+func (c *ProvenanceClient) GetScopeSpecContext(ctx context.Context, specId string) (*meta.ScopeSpecification, error) {
+	res, err := (*c.MetadataClient()).ScopeSpecification(ctx, &meta.ScopeSpecificationRequest{
 		SpecificationId:      specId,
 		IncludeContractSpecs: false,
 		IncludeRecordSpecs:   false,
@@ -118,10 +149,18 @@ func (c *ProvenanceClient) GetScopeSpec(specId string) (*meta.ScopeSpecification
 	return res.ScopeSpecification.Specification, nil
 }
 
+// GetRecordSpec is GetRecordSpecContext with a background context.
+//
+// Deprecated: use GetRecordSpecContext.
 func (c *ProvenanceClient) GetRecordSpec(contractSpecUUID string, recordName string) (*meta.RecordSpecification, error) {
+	return c.GetRecordSpecContext(context.Background(), contractSpecUUID, recordName)
+}
+
+// GetRecordSpecContext returns the record specification named recordName in a contract specification.
+func (c *ProvenanceClient) GetRecordSpecContext(ctx context.Context, contractSpecUUID string, recordName string) (*meta.RecordSpecification, error) {
 	specId := meta.RecordSpecMetadataAddress(uuid.MustParse(contractSpecUUID), recordName)
 
-	res, err := (*c.MetadataClient()).RecordSpecification(context.Background(), &meta.RecordSpecificationRequest{
+	res, err := (*c.MetadataClient()).RecordSpecification(ctx, &meta.RecordSpecificationRequest{
 		SpecificationId: specId.String(),
 	})
 	if err != nil {
@@ -131,8 +170,16 @@ func (c *ProvenanceClient) GetRecordSpec(contractSpecUUID string, recordName str
 	return res.RecordSpecification.Specification, nil
 }
 
+// GetScope is GetScopeContext with a background context.
+//
+// Deprecated: use GetScopeContext.
 func (c *ProvenanceClient) GetScope(scopeUuid string) (*meta.Scope, error) {
-	res, err := (*c.MetadataClient()).Scope(context.Background(), &meta.ScopeRequest{
+	return c.GetScopeContext(context.Background(), scopeUuid)
+}
+
+// GetScopeContext returns a scope by id or UUID.
+func (c *ProvenanceClient) GetScopeContext(ctx context.Context, scopeUuid string) (*meta.Scope, error) {
+	res, err := (*c.MetadataClient()).Scope(ctx, &meta.ScopeRequest{
 		ScopeId: scopeUuid,
 	})
 	if err != nil {
@@ -0,0 +1,129 @@
+package provenance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	xauthsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+// NewMultisigPubKey returns the legacy amino multisig key that threshold of members must sign for. The
+// key, and so the account address, depends on the order of members; `provenanced keys add --multisig`
+// sorts them by address unless --nosort is given, which SortPubKeys reproduces.
+func NewMultisigPubKey(threshold int, members []cryptotypes.PubKey) (*kmultisig.LegacyAminoPubKey, error) {
+	if threshold <= 0 || threshold > len(members) {
+		return nil, fmt.Errorf("threshold %d out of range for %d members", threshold, len(members))
+	}
+	for i, pk := range members {
+		for _, other := range members[:i] {
+			if pk.Equals(other) {
+				return nil, fmt.Errorf("duplicate member %s", pk.Address())
+			}
+		}
+	}
+	return kmultisig.NewLegacyAminoPubKey(threshold, members), nil
+}
+
+// SortPubKeys sorts keys by address in place, the member order `provenanced keys add --multisig` uses.
+func SortPubKeys(keys []cryptotypes.PubKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i].Address(), keys[j].Address()) < 0
+	})
+}
+
+// SignMultisigPartial signs a transaction in JSON form, such as UnsignedTx.JSON, as one member of the
+// multisig account key and returns the member's signature as JSON, in the format of
+// `provenanced tx sign --multisig`. params are those of the multisig account. Members sign with
+// SIGN_MODE_LEGACY_AMINO_JSON, the only mode legacy amino multisigs support. It does not contact a node.
+func SignMultisigPartial(ctx context.Context, signer Signer, key multisig.PubKey, txJSON []byte, params OfflineSignParams) ([]byte, error) {
+	if !isMember(key, signer.PubKey()) {
+		return nil, fmt.Errorf("signer is not a member of the multisig")
+	}
+	txConfig, txBuilder, data, err := decodeForSigning(txJSON, key.Address().Bytes(), key, params)
+	if err != nil {
+		return nil, err
+	}
+
+	signBytes, err := xauthsigning.GetSignBytesAdapter(ctx, txConfig.SignModeHandler(), signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, data, txBuilder.GetTx())
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signInMode(ctx, signer, signBytes, signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON)
+	if err != nil {
+		return nil, fmt.Errorf("error signing tx: %w", err)
+	}
+
+	return txConfig.MarshalSignatureJSON([]signing.SignatureV2{{
+		PubKey: signer.PubKey(),
+		Data: &signing.SingleSignatureData{
+			SignMode:  signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON,
+			Signature: signature,
+		},
+		Sequence: params.Sequence,
+	}})
+}
+
+// CombineMultisigSignatures verifies member signatures made by SignMultisigPartial, combines them into
+// the multisig account's signature on txJSON, and returns the signed transaction as JSON, ready for
+// BroadcastSignedTx. It fails unless at least the key's threshold of distinct members have signed.
+func CombineMultisigSignatures(key multisig.PubKey, txJSON []byte, params OfflineSignParams, partials ...[]byte) ([]byte, error) {
+	txConfig, txBuilder, data, err := decodeForSigning(txJSON, key.Address().Bytes(), key, params)
+	if err != nil {
+		return nil, err
+	}
+	signBytes, err := xauthsigning.GetSignBytesAdapter(context.Background(), txConfig.SignModeHandler(), signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, data, txBuilder.GetTx())
+	if err != nil {
+		return nil, err
+	}
+
+	members := key.GetPubKeys()
+	combined := multisig.NewMultisig(len(members))
+	for _, partial := range partials {
+		sigs, err := txConfig.UnmarshalSignatureJSON(partial)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding signature: %w", err)
+		}
+		for _, sig := range sigs {
+			single, ok := sig.Data.(*signing.SingleSignatureData)
+			if !ok || single.SignMode != signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON {
+				return nil, fmt.Errorf("signature by %s is not a single amino json signature", sig.PubKey.Address())
+			}
+			if !sig.PubKey.VerifySignature(signBytes, single.Signature) {
+				return nil, fmt.Errorf("signature by %s does not verify", sig.PubKey.Address())
+			}
+			if err := multisig.AddSignatureV2(combined, sig, members); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(combined.Signatures) < int(key.GetThreshold()) {
+		return nil, fmt.Errorf("%d of %d required signatures", len(combined.Signatures), key.GetThreshold())
+	}
+
+	// Keep the signatures of any other signers, such as a separate fee payer.
+	err = setSignature(txBuilder, signing.SignatureV2{
+		PubKey:   key,
+		Data:     combined,
+		Sequence: params.Sequence,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return txConfig.TxJSONEncoder()(txBuilder.GetTx())
+}
+
+func isMember(key multisig.PubKey, pk cryptotypes.PubKey) bool {
+	for _, member := range key.GetPubKeys() {
+		if member.Equals(pk) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package provenance
+
+import (
+	"context"
+	"testing"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	xauthsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+)
+
+func TestMultisigSignAndCombine(t *testing.T) {
+	t.Parallel()
+	conf := NewLocalnetConfig()
+	var members []Signer
+	var keys []cryptotypes.PubKey
+	for _, m := range []string{
+		offlineTestMnemonic,
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+	} {
+		s, err := NewMnemonicSigner(conf, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		members = append(members, s)
+		keys = append(keys, s.PubKey())
+	}
+	if _, err := NewMultisigPubKey(4, keys); err == nil {
+		t.Fatal("expected an error for a threshold above the member count")
+	}
+	if _, err := NewMultisigPubKey(2, append(keys, keys[0])); err == nil {
+		t.Fatal("expected an error for a duplicate member")
+	}
+	key, err := NewMultisigPubKey(2, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := OfflineSignParams{ChainID: conf.ChainID(), AddressPrefix: conf.AddressPrefix(), AccountNumber: 12, Sequence: 4}
+	unsigned := unsignedSend(t, conf, key.Address().Bytes())
+
+	var partials [][]byte
+	for _, s := range []Signer{members[2], members[0]} {
+		partial, err := SignMultisigPartial(context.Background(), s, key, unsigned, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		partials = append(partials, partial)
+	}
+	outsider, err := NewMnemonicSigner(conf, "all all all all all all all all all all all all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SignMultisigPartial(context.Background(), outsider, key, unsigned, params); err == nil {
+		t.Fatal("expected an error signing as a non-member")
+	}
+
+	if _, err := CombineMultisigSignatures(key, unsigned, params, partials[0], partials[0]); err == nil {
+		t.Fatal("expected an error combining one member's signature twice")
+	}
+	wrongSeq := params
+	wrongSeq.Sequence++
+	if _, err := CombineMultisigSignatures(key, unsigned, wrongSeq, partials...); err == nil {
+		t.Fatal("expected an error combining signatures made for another sequence")
+	}
+	signed, err := CombineMultisigSignatures(key, unsigned, params, partials...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The combined signature verifies as the chain would check it.
+	cdc, _ := NewCodec(conf.AddressPrefix())
+	txConfig := authtx.NewTxConfig(cdc, authtx.DefaultSignModes)
+	tx, err := txConfig.TxJSONDecoder()(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigTx := tx.(xauthsigning.Tx)
+	sigs, err := sigTx.GetSignaturesV2()
+	if err != nil || len(sigs) != 1 || !sigs[0].PubKey.Equals(key) {
+		t.Fatalf("signatures: %v %v", sigs, err)
+	}
+	addr, _ := cdc.InterfaceRegistry().SigningContext().AddressCodec().BytesToString(key.Address())
+	data := xauthsigning.SignerData{Address: addr, ChainID: params.ChainID, AccountNumber: params.AccountNumber, Sequence: params.Sequence, PubKey: key}
+	getSignBytes := func(mode signing.SignMode) ([]byte, error) {
+		return xauthsigning.GetSignBytesAdapter(context.Background(), txConfig.SignModeHandler(), mode, data, sigTx)
+	}
+	if err := key.VerifyMultisignature(getSignBytes, sigs[0].Data.(*signing.MultiSignatureData)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlaceholderDataMultisig(t *testing.T) {
+	t.Parallel()
+	conf := NewLocalnetConfig()
+	var keys []cryptotypes.PubKey
+	for _, m := range []string{offlineTestMnemonic, "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong"} {
+		s, err := NewMnemonicSigner(conf, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, s.PubKey())
+	}
+	key, err := NewMultisigPubKey(1, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, ok := placeholderData(key).(*signing.MultiSignatureData)
+	if !ok || len(data.Signatures) != 1 || !data.BitArray.GetIndex(0) {
+		t.Fatalf("placeholder: %+v", placeholderData(key))
+	}
+}
@@ -0,0 +1,409 @@
+package provenance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	xauthsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// OfflineSignParams is everything a signature commits to besides the transaction itself, so that a
+// transaction can be signed without access to a node.
+type OfflineSignParams struct {
+	ChainID       string `json:"chain_id"`
+	AddressPrefix string `json:"address_prefix"`
+	AccountNumber uint64 `json:"account_number"`
+	Sequence      uint64 `json:"sequence"`
+	// SignMode is the mode to sign in; SIGN_MODE_UNSPECIFIED means SIGN_MODE_DIRECT, or
+	// SIGN_MODE_LEGACY_AMINO_JSON for a transaction with more than one signer, which cannot be signed
+	// in DIRECT. SIGN_MODE_TEXTUAL needs denom metadata from a node, so it cannot be used offline.
+	SignMode signing.SignMode `json:"sign_mode,omitempty"`
+}
+
+// UnsignedTx is a transaction ready to be carried to an offline signer.
+type UnsignedTx struct {
+	// JSON is the transaction in the format of `provenanced tx ... --generate-only`.
+	JSON []byte `json:"tx"`
+	// Params are the signer's account details as of when the transaction was built.
+	Params OfflineSignParams `json:"params"`
+}
+
+// BuildUnsignedTx builds a transaction for msgs to be signed offline by signer, a bech32 address. The
+// gas and fee are estimated as SignTxContext does, which needs the signer's public key: it is taken from
+// the client's Signer when that is the signer, and otherwise from the chain, so accounts that have never
+// signed a transaction must pass WithGasLimit.
+func (c *ProvenanceClient) BuildUnsignedTx(ctx context.Context, signer string, msgs []sdk.Msg, opts ...TxOption) (*UnsignedTx, error) {
+	acc, pubKey, err := c.getAccount(ctx, signer)
+	if err != nil {
+		return nil, fmt.Errorf("error getting signer account: %w", err)
+	}
+	if pubKey == nil && c.Signer != nil && c.Address == signer {
+		pubKey = c.Signer.PubKey()
+	}
+
+	txOpts := NewTxOptions(opts...)
+	if txOpts.GasLimit == 0 && pubKey == nil {
+		return nil, fmt.Errorf("account %s has no public key on chain to simulate with; pass WithGasLimit", signer)
+	}
+	txConfig := c.txConfig()
+
+	txBuilder, _, err := c.buildTx(ctx, msgs, pubKey, acc.Sequence, txOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Like --generate-only, the unsigned tx carries no signer infos.
+	if err := txBuilder.SetSignatures(); err != nil {
+		return nil, err
+	}
+	txJSON, err := txConfig.TxJSONEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnsignedTx{
+		JSON: txJSON,
+		Params: OfflineSignParams{
+			ChainID:       c.BcConfig.ChainID(),
+			AddressPrefix: c.BcConfig.AddressPrefix(),
+			AccountNumber: acc.AccountNumber,
+			Sequence:      acc.Sequence,
+		},
+	}, nil
+}
+
+// getAccount returns the base account at address and its public key, which is nil until the account
+// has signed a transaction.
+func (c *ProvenanceClient) getAccount(ctx context.Context, address string) (*authtypes.BaseAccount, cryptotypes.PubKey, error) {
+	res, err := (*c.AuthClient()).Account(ctx, &authtypes.QueryAccountRequest{Address: address})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var acc authtypes.BaseAccount
+	if err := acc.Unmarshal(res.Account.Value); err != nil {
+		return nil, nil, err
+	}
+	if acc.PubKey == nil {
+		return &acc, nil, nil
+	}
+
+	var pubKey cryptotypes.PubKey
+	if err := c.Cdc.UnpackAny(acc.PubKey, &pubKey); err != nil {
+		return nil, nil, fmt.Errorf("error unpacking public key: %w", err)
+	}
+	return &acc, pubKey, nil
+}
+
+// SignTxOffline adds signer's signature to a transaction in JSON form, such as UnsignedTx.JSON, and
+// returns the signed transaction as JSON. It does not contact a node.
+func SignTxOffline(ctx context.Context, signer Signer, txJSON []byte, params OfflineSignParams) ([]byte, error) {
+	txConfig, txBuilder, data, err := decodeForSigning(txJSON, signer.Address(), signer.PubKey(), params)
+	if err != nil {
+		return nil, err
+	}
+	if err := signSingle(ctx, txConfig, txBuilder, signer, data, params.SignMode); err != nil {
+		return nil, err
+	}
+
+	return txConfig.TxJSONEncoder()(txBuilder.GetTx())
+}
+
+// decodeForSigning decodes txJSON for signing by the account at address with pubKey, checking that
+// the account is one of the transaction's signers.
+func decodeForSigning(txJSON []byte, address sdk.AccAddress, pubKey cryptotypes.PubKey, params OfflineSignParams) (client.TxConfig, client.TxBuilder, xauthsigning.SignerData, error) {
+	var data xauthsigning.SignerData
+	if params.ChainID == "" || params.AddressPrefix == "" {
+		return nil, nil, data, fmt.Errorf("chain id and address prefix are required to sign offline")
+	}
+	if params.SignMode == signing.SignMode_SIGN_MODE_TEXTUAL {
+		return nil, nil, data, fmt.Errorf("%s cannot be used offline", params.SignMode)
+	}
+	cdc, err := NewCodec(params.AddressPrefix)
+	if err != nil {
+		return nil, nil, data, err
+	}
+	txConfig := authtx.NewTxConfig(cdc, authtx.DefaultSignModes)
+
+	tx, err := txConfig.TxJSONDecoder()(txJSON)
+	if err != nil {
+		return nil, nil, data, fmt.Errorf("error decoding tx: %w", err)
+	}
+	txBuilder, err := txConfig.WrapTxBuilder(tx)
+	if err != nil {
+		return nil, nil, data, err
+	}
+
+	signers, err := txBuilder.GetTx().GetSigners()
+	if err != nil {
+		return nil, nil, data, fmt.Errorf("error getting tx signers: %w", err)
+	}
+	if !containsAddress(signers, address) {
+		return nil, nil, data, fmt.Errorf("signer is not one of the transaction's signers")
+	}
+
+	bech32, err := cdc.InterfaceRegistry().SigningContext().AddressCodec().BytesToString(address)
+	if err != nil {
+		return nil, nil, data, err
+	}
+	data = xauthsigning.SignerData{
+		Address:       bech32,
+		ChainID:       params.ChainID,
+		AccountNumber: params.AccountNumber,
+		Sequence:      params.Sequence,
+		PubKey:        pubKey,
+	}
+	return txConfig, txBuilder, data, nil
+}
+
+// SignTxFile signs the JSON transaction in inPath with SignTxOffline and writes the result to outPath.
+func SignTxFile(ctx context.Context, signer Signer, inPath, outPath string, params OfflineSignParams) error {
+	txJSON, err := os.ReadFile(inPath)
+	if err != nil {
+		return fmt.Errorf("error reading tx: %w", err)
+	}
+	signed, err := SignTxOffline(ctx, signer, txJSON, params)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, signed, 0o600); err != nil {
+		return fmt.Errorf("error writing signed tx: %w", err)
+	}
+	return nil
+}
+
+// BroadcastSignedTx broadcasts a transaction signed elsewhere, given either as JSON or in its binary
+// encoding. Unlike SignAndBroadcast it leaves the client's sequence alone.
+func (c *ProvenanceClient) BroadcastSignedTx(ctx context.Context, tx []byte, opts ...BroadcastOption) (*txtypes.BroadcastTxResponse, error) {
+	txBz, err := TxToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	sigTx, err := DecodeTx(txBz)
+	if err != nil {
+		return nil, err
+	}
+	sigs, err := sigTx.GetSignaturesV2()
+	if err != nil {
+		return nil, err
+	}
+	if len(sigs) == 0 {
+		return nil, fmt.Errorf("tx is not signed")
+	}
+	return c.BroadcastTxContext(ctx, txBz, opts...)
+}
+
+// placeholderSignature is an empty signature that lets simulation account for pubKey's signature. For
+// a multisig key it holds a threshold of empty member signatures, which is what the chain charges gas for.
+func placeholderSignature(pubKey cryptotypes.PubKey, sequence uint64) signing.SignatureV2 {
+	return signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     placeholderData(pubKey),
+		Sequence: sequence,
+	}
+}
+
+func placeholderData(pubKey cryptotypes.PubKey) signing.SignatureData {
+	mpk, ok := pubKey.(multisig.PubKey)
+	if !ok {
+		return &signing.SingleSignatureData{
+			SignMode:  signing.SignMode_SIGN_MODE_DIRECT,
+			Signature: nil,
+		}
+	}
+	keys := mpk.GetPubKeys()
+	data := multisig.NewMultisig(len(keys))
+	for i := 0; i < int(mpk.GetThreshold()); i++ {
+		multisig.AddSignature(data, placeholderData(keys[i]), i)
+	}
+	return data
+}
+
+// signSingle signs txBuilder's transaction in mode, keeping the signatures of any other signers
+// already on it. An unspecified mode is SIGN_MODE_DIRECT, or SIGN_MODE_LEGACY_AMINO_JSON when the
+// transaction has more than one signer.
+func signSingle(ctx context.Context, txConfig client.TxConfig, txBuilder client.TxBuilder, signer Signer, data xauthsigning.SignerData, mode signing.SignMode) error {
+	signers, err := txBuilder.GetTx().GetSigners()
+	if err != nil {
+		return fmt.Errorf("error getting tx signers: %w", err)
+	}
+	switch {
+	case len(signers) > 1 && mode == signing.SignMode_SIGN_MODE_UNSPECIFIED:
+		mode = signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+	case len(signers) > 1 && (mode == signing.SignMode_SIGN_MODE_DIRECT || mode == signing.SignMode_SIGN_MODE_TEXTUAL):
+		// Each signer adds its signer info, which would change what the others signed.
+		return fmt.Errorf("%s cannot be used on a tx with %d signers: sign with %s", mode, len(signers), signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON)
+	case mode == signing.SignMode_SIGN_MODE_UNSPECIFIED:
+		mode = signing.SignMode_SIGN_MODE_DIRECT
+	}
+
+	// The signer info, sign mode included, is part of what DIRECT and TEXTUAL sign, so it has to be
+	// in place first.
+	sig := signing.SignatureV2{
+		PubKey:   data.PubKey,
+		Data:     &signing.SingleSignatureData{SignMode: mode},
+		Sequence: data.Sequence,
+	}
+	if err := setSignature(txBuilder, sig); err != nil {
+		return err
+	}
+
+	signBytes, err := xauthsigning.GetSignBytesAdapter(ctx, txConfig.SignModeHandler(), mode, data, txBuilder.GetTx())
+	if err != nil {
+		return err
+	}
+	signature, err := signInMode(ctx, signer, signBytes, mode)
+	if err != nil {
+		return fmt.Errorf("error signing tx: %w", err)
+	}
+
+	sig.Data = &signing.SingleSignatureData{SignMode: mode, Signature: signature}
+	return setSignature(txBuilder, sig)
+}
+
+// setSignature puts sig on txBuilder's transaction at its signer's index in GetSigners(), which is how
+// the chain pairs signatures with signers, replacing any earlier signature by the same key and keeping
+// those of the other signers. Signers before it that have not signed yet get an empty entry to hold
+// their place: a signature with an empty public key, as the signer's key may not be known yet.
+func setSignature(txBuilder client.TxBuilder, sig signing.SignatureV2) error {
+	tx := txBuilder.GetTx()
+	signers, err := tx.GetSigners()
+	if err != nil {
+		return fmt.Errorf("error getting tx signers: %w", err)
+	}
+	sigs, err := tx.GetSignaturesV2()
+	if err != nil {
+		return err
+	}
+
+	slots := make([]signing.SignatureV2, len(signers))
+	for i := range slots {
+		slots[i] = signing.SignatureV2{PubKey: &secp256k1.PubKey{}}
+	}
+	last := -1
+	for _, s := range append(sigs, sig) {
+		if s.PubKey == nil || len(s.PubKey.Bytes()) == 0 {
+			// A placeholder, kept only while a later signer has signed.
+			continue
+		}
+		i := slices.IndexFunc(signers, func(addr []byte) bool { return bytes.Equal(addr, s.PubKey.Address()) })
+		if i < 0 {
+			return fmt.Errorf("signature by %s is not from one of the transaction's signers", sdk.AccAddress(s.PubKey.Address()))
+		}
+		slots[i] = s
+		last = max(last, i)
+	}
+	return txBuilder.SetSignatures(slots[:last+1]...)
+}
+
+func containsAddress(addrs [][]byte, addr sdk.AccAddress) bool {
+	for _, a := range addrs {
+		if bytes.Equal(a, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// TxSummary describes a transaction for review before it is signed or broadcast.
+type TxSummary struct {
+	// Hash is the hash the transaction will be known by once broadcast.
+	Hash          string
+	Msgs          []sdk.Msg
+	Memo          string
+	Fee           sdk.Coins
+	GasLimit      uint64
+	TimeoutHeight uint64
+	// Sequences holds the sequence of each signature, in signer order. It is empty for unsigned txs.
+	Sequences []uint64
+}
+
+// DecodeTx decodes a transaction given either as JSON or in its binary encoding.
+func DecodeTx(tx []byte) (xauthsigning.Tx, error) {
+	txConfig := NewTxConfig()
+
+	var decoded sdk.Tx
+	var err error
+	if isJSON(tx) {
+		decoded, err = txConfig.TxJSONDecoder()(tx)
+	} else {
+		decoded, err = txConfig.TxDecoder()(tx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding tx: %w", err)
+	}
+
+	sigTx, ok := decoded.(xauthsigning.Tx)
+	if !ok {
+		return nil, fmt.Errorf("unexpected tx type %T", decoded)
+	}
+	return sigTx, nil
+}
+
+// TxToBytes returns the binary encoding of a transaction given either as JSON or already encoded.
+func TxToBytes(tx []byte) ([]byte, error) {
+	if !isJSON(tx) {
+		return tx, nil
+	}
+	decoded, err := DecodeTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return NewTxConfig().TxEncoder()(decoded)
+}
+
+// TxToJSON returns the JSON form of a transaction given either in its binary encoding or as JSON.
+func TxToJSON(tx []byte) ([]byte, error) {
+	decoded, err := DecodeTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return NewTxConfig().TxJSONEncoder()(decoded)
+}
+
+// InspectTx summarizes a transaction given either as JSON or in its binary encoding.
+func InspectTx(tx []byte) (*TxSummary, error) {
+	txBz, err := TxToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := DecodeTx(txBz)
+	if err != nil {
+		return nil, err
+	}
+	sigs, err := decoded.GetSignaturesV2()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &TxSummary{
+		Hash:          fmt.Sprintf("%X", cmttypes.Tx(txBz).Hash()),
+		Msgs:          decoded.GetMsgs(),
+		Memo:          decoded.GetMemo(),
+		Fee:           decoded.GetFee(),
+		GasLimit:      decoded.GetGas(),
+		TimeoutHeight: decoded.GetTimeoutHeight(),
+	}
+	for _, sig := range sigs {
+		summary.Sequences = append(summary.Sequences, sig.Sequence)
+	}
+	return summary, nil
+}
+
+func isJSON(bz []byte) bool {
+	trimmed := bytes.TrimSpace(bz)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
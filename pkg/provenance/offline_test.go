@@ -0,0 +1,258 @@
+package provenance
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	xauthsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+)
+
+const offlineTestMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// unsignedSend builds the --generate-only JSON of a bank send from addr.
+func unsignedSend(t *testing.T, conf BlockchainConfigProvider, addr sdk.AccAddress) []byte {
+	t.Helper()
+	cdc, err := NewCodec(conf.AddressPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	txConfig := authtx.NewTxConfig(cdc, authtx.DefaultSignModes)
+	from, err := cdc.InterfaceRegistry().SigningContext().AddressCodec().BytesToString(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := txConfig.NewTxBuilder()
+	coins := sdk.NewCoins(sdk.NewInt64Coin("nhash", 5))
+	if err := b.SetMsgs(&banktypes.MsgSend{FromAddress: from, ToAddress: from, Amount: coins}); err != nil {
+		t.Fatal(err)
+	}
+	b.SetGasLimit(200000)
+	b.SetFeeAmount(coins)
+	b.SetMemo("offline")
+	txJSON, err := txConfig.TxJSONEncoder()(b.GetTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return txJSON
+}
+
+func TestSignTxOffline(t *testing.T) {
+	t.Parallel()
+	conf := NewLocalnetConfig()
+	signer, err := NewMnemonicSigner(conf, offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := OfflineSignParams{ChainID: conf.ChainID(), AddressPrefix: conf.AddressPrefix(), AccountNumber: 3, Sequence: 9}
+
+	dir := t.TempDir()
+	in, out := filepath.Join(dir, "unsigned.json"), filepath.Join(dir, "signed.json")
+	if err := os.WriteFile(in, unsignedSend(t, conf, signer.Address()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SignTxFile(context.Background(), signer, in, out, params); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The signature verifies against the offline params.
+	cdc, _ := NewCodec(conf.AddressPrefix())
+	txConfig := authtx.NewTxConfig(cdc, authtx.DefaultSignModes)
+	tx, err := txConfig.TxJSONDecoder()(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigTx := tx.(xauthsigning.Tx)
+	sigs, err := sigTx.GetSignaturesV2()
+	if err != nil || len(sigs) != 1 {
+		t.Fatalf("signatures: %v %v", sigs, err)
+	}
+	from, _ := cdc.InterfaceRegistry().SigningContext().AddressCodec().BytesToString(signer.Address())
+	signBytes, err := xauthsigning.GetSignBytesAdapter(context.Background(), txConfig.SignModeHandler(), signing.SignMode_SIGN_MODE_DIRECT, xauthsigning.SignerData{
+		Address:       from,
+		ChainID:       params.ChainID,
+		AccountNumber: params.AccountNumber,
+		Sequence:      params.Sequence,
+		PubKey:        signer.PubKey(),
+	}, sigTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signer.PubKey().VerifySignature(signBytes, sigs[0].Data.(*signing.SingleSignatureData).Signature) {
+		t.Fatal("signature does not verify")
+	}
+
+	summary, err := InspectTx(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Memo != "offline" || summary.GasLimit != 200000 || len(summary.Msgs) != 1 || len(summary.Sequences) != 1 || summary.Sequences[0] != 9 {
+		t.Fatalf("summary: %+v", summary)
+	}
+
+	// JSON and binary encodings round trip.
+	bz, err := TxToBytes(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := TxToJSON(bz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(back, signed) {
+		t.Fatalf("round trip:\n%s\n%s", back, signed)
+	}
+}
+
+func TestSignTxOfflineRejectsOtherSigner(t *testing.T) {
+	t.Parallel()
+	conf := NewLocalnetConfig()
+	signer, err := NewMnemonicSigner(conf, offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewMnemonicSigner(conf, "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := OfflineSignParams{ChainID: conf.ChainID(), AddressPrefix: conf.AddressPrefix()}
+	if _, err := SignTxOffline(context.Background(), other, unsignedSend(t, conf, signer.Address()), params); err == nil {
+		t.Fatal("expected an error signing for another account")
+	}
+}
+
+func TestSignTxOfflineSignerOrder(t *testing.T) {
+	t.Parallel()
+	conf := NewLocalnetConfig()
+	signer, err := NewMnemonicSigner(conf, offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payer, err := NewMnemonicSigner(conf, "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A send from signer whose fee is paid by payer, so the signers are [signer, payer].
+	cdc, _ := NewCodec(conf.AddressPrefix())
+	txConfig := authtx.NewTxConfig(cdc, authtx.DefaultSignModes)
+	tx, err := txConfig.TxJSONDecoder()(unsignedSend(t, conf, signer.Address()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := txConfig.WrapTxBuilder(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetFeePayer(payer.Address())
+	unsigned, err := txConfig.TxJSONEncoder()(b.GetTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SetFeePayer encodes with the global SDK prefix.
+	payerAddr, _ := cdc.InterfaceRegistry().SigningContext().AddressCodec().BytesToString(payer.Address())
+	unsigned = bytes.ReplaceAll(unsigned, []byte(sdk.AccAddress(payer.Address()).String()), []byte(payerAddr))
+
+	// DIRECT signs the signer infos, which each signer adds to, so it is refused.
+	params := OfflineSignParams{ChainID: conf.ChainID(), AddressPrefix: conf.AddressPrefix(), SignMode: signing.SignMode_SIGN_MODE_DIRECT}
+	if _, err := SignTxOffline(context.Background(), payer, unsigned, params); err == nil {
+		t.Fatal("expected an error signing a tx with two signers in DIRECT")
+	}
+
+	// Amino JSON, which is also the default here, leaves every signature valid. The fee payer signs first.
+	for _, mode := range []signing.SignMode{signing.SignMode_SIGN_MODE_UNSPECIFIED, signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON} {
+		params.SignMode = mode
+		signed, err := SignTxOffline(context.Background(), payer, unsigned, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signed, err = SignTxOffline(context.Background(), signer, signed, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sigTx, err := DecodeTx(signed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs, err := sigTx.GetSignaturesV2()
+		if err != nil || len(sigs) != 2 {
+			t.Fatalf("%s: signatures: %v %v", mode, sigs, err)
+		}
+		for i, want := range []Signer{signer, payer} {
+			if !sigs[i].PubKey.Equals(want.PubKey()) {
+				t.Fatalf("%s: signature %d is by %s want %s", mode, i, sigs[i].PubKey.Address(), want.PubKey().Address())
+			}
+			addr, _ := cdc.InterfaceRegistry().SigningContext().AddressCodec().BytesToString(want.Address())
+			signBytes, err := xauthsigning.GetSignBytesAdapter(context.Background(), txConfig.SignModeHandler(), signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, xauthsigning.SignerData{
+				Address: addr,
+				ChainID: params.ChainID,
+				PubKey:  want.PubKey(),
+			}, sigTx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !want.PubKey().VerifySignature(signBytes, sigs[i].Data.(*signing.SingleSignatureData).Signature) {
+				t.Fatalf("%s: signature %d does not verify", mode, i)
+			}
+		}
+	}
+}
+
+// recordingTxService keeps the last tx it was asked to broadcast.
+type recordingTxService struct {
+	txtypes.UnimplementedServiceServer
+	got chan []byte
+}
+
+func (s *recordingTxService) BroadcastTx(_ context.Context, req *txtypes.BroadcastTxRequest) (*txtypes.BroadcastTxResponse, error) {
+	s.got <- req.TxBytes
+	return &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: "ABC"}}, nil
+}
+
+func TestBroadcastSignedTx(t *testing.T) {
+	t.Parallel()
+	conf := NewLocalnetConfig()
+	signer, err := NewMnemonicSigner(conf, offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := unsignedSend(t, conf, signer.Address())
+	signed, err := SignTxOffline(context.Background(), signer, unsigned, OfflineSignParams{ChainID: conf.ChainID(), AddressPrefix: conf.AddressPrefix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := &recordingTxService{got: make(chan []byte, 1)}
+	conn := serveTestNode(t, func(srv *grpc.Server) {
+		txtypes.RegisterServiceServer(srv, svc)
+	})
+	c := &ProvenanceClient{Grpc: conn, BcConfig: conf}
+
+	if _, err := c.BroadcastSignedTx(context.Background(), unsigned); err == nil {
+		t.Fatal("expected an error broadcasting an unsigned tx")
+	}
+	if _, err := c.BroadcastSignedTx(context.Background(), signed); err != nil {
+		t.Fatal(err)
+	}
+	want, err := TxToBytes(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := <-svc.got; !bytes.Equal(got, want) {
+		t.Fatal("broadcast bytes differ from the signed tx")
+	}
+}
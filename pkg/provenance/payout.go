@@ -0,0 +1,335 @@
+package provenance
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Payment is one recipient of a Payout.
+type Payment struct {
+	Recipient string    `json:"recipient"`
+	Amount    sdk.Coins `json:"amount"`
+	// Reference identifies the payment in the report, e.g. an invoice or employee ID.
+	Reference string `json:"reference,omitempty"`
+}
+
+// PaymentStatus is the outcome of a single payment.
+type PaymentStatus string
+
+const (
+	// PaymentSent means the payment was included in a block and succeeded.
+	PaymentSent PaymentStatus = "sent"
+	// PaymentFailed means the chain rejected the payment, so no funds moved.
+	PaymentFailed PaymentStatus = "failed"
+	// PaymentUnknown means the payment was broadcast but its inclusion could not be confirmed. Look
+	// TxHash up before paying again.
+	PaymentUnknown PaymentStatus = "unknown"
+	// PaymentSkipped means the payout stopped before it got to this payment.
+	PaymentSkipped PaymentStatus = "skipped"
+)
+
+// PaymentResult is a Payment with its outcome.
+type PaymentResult struct {
+	Payment
+	Status PaymentStatus `json:"status"`
+	TxHash string        `json:"tx_hash,omitempty"`
+	Height int64         `json:"height,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// PayoutReport lists the outcome of every payment of a Payout, in the order they were given.
+type PayoutReport struct {
+	Results []PaymentResult `json:"results"`
+}
+
+// Count returns how many payments ended with s.
+func (r *PayoutReport) Count(s PaymentStatus) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == s {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteJSON writes the report as JSON.
+func (r *PayoutReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes the report as CSV with a header row.
+func (r *PayoutReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"recipient", "amount", "reference", "status", "tx_hash", "height", "error"}); err != nil {
+		return err
+	}
+	for _, res := range r.Results {
+		height := ""
+		if res.Height != 0 {
+			height = strconv.FormatInt(res.Height, 10)
+		}
+		row := []string{res.Recipient, res.Amount.String(), res.Reference, string(res.Status), res.TxHash, height, res.Error}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// PayoutOptions controls how Payout splits payments into transactions. Build it with PayoutOption
+// values; zero fields fall back to DefaultPayoutOptions.
+type PayoutOptions struct {
+	// BatchSize is the most recipients paid by one transaction.
+	BatchSize int
+	// MaxGas bounds the gas limit of each transaction. Batches estimated above it are split in half.
+	MaxGas uint64
+	// TxOptions apply to every transaction. A gas limit is always set from the batch's estimate.
+	TxOptions []TxOption
+	// WaitOptions control the wait for each transaction's inclusion.
+	WaitOptions []WaitOption
+}
+
+// DefaultPayoutOptions pays up to 100 recipients per transaction, in transactions of at most 4M gas.
+var DefaultPayoutOptions = PayoutOptions{
+	BatchSize: 100,
+	MaxGas:    4_000_000,
+}
+
+// PayoutOption configures a single PayoutOptions field.
+type PayoutOption func(*PayoutOptions)
+
+func WithBatchSize(n int) PayoutOption {
+	return func(o *PayoutOptions) {
+		o.BatchSize = n
+	}
+}
+
+func WithMaxGas(gas uint64) PayoutOption {
+	return func(o *PayoutOptions) {
+		o.MaxGas = gas
+	}
+}
+
+func WithPayoutTxOptions(opts ...TxOption) PayoutOption {
+	return func(o *PayoutOptions) {
+		o.TxOptions = append(o.TxOptions, opts...)
+	}
+}
+
+func WithPayoutWaitOptions(opts ...WaitOption) PayoutOption {
+	return func(o *PayoutOptions) {
+		o.WaitOptions = append(o.WaitOptions, opts...)
+	}
+}
+
+// Payout pays every payment from the client's address with multi-send transactions of at most
+// BatchSize recipients and MaxGas gas, sent one after another, each waited on until it is included.
+//
+// Before sending anything it checks every recipient and amount, and that the client's balance covers
+// the total of each denom; fees are not part of that check. A batch the chain refuses to simulate is
+// split until the payments it refuses are isolated and reported as failed. Payout stops early, and
+// returns the report along with an error, when ctx ends or a transaction's outcome cannot be
+// confirmed; later payments are reported as skipped.
+func (c *ProvenanceClient) Payout(ctx context.Context, payments []Payment, opts ...PayoutOption) (*PayoutReport, error) {
+	var o PayoutOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultPayoutOptions.BatchSize
+	}
+	if o.MaxGas == 0 {
+		o.MaxGas = DefaultPayoutOptions.MaxGas
+	}
+
+	if c.Signer == nil {
+		return nil, fmt.Errorf("provenance client has no signer")
+	}
+	if err := c.checkPayments(ctx, payments); err != nil {
+		return nil, err
+	}
+
+	report := &PayoutReport{Results: make([]PaymentResult, len(payments))}
+	for i, p := range payments {
+		report.Results[i] = PaymentResult{Payment: p, Status: PaymentSkipped}
+	}
+
+	var pending [][]int
+	for start := 0; start < len(payments); start += o.BatchSize {
+		batch := make([]int, 0, o.BatchSize)
+		for i := start; i < len(payments) && i < start+o.BatchSize; i++ {
+			batch = append(batch, i)
+		}
+		pending = append(pending, batch)
+	}
+
+	for len(pending) > 0 {
+		batch := pending[0]
+		pending = pending[1:]
+
+		split, err := c.payBatch(ctx, report, batch, o)
+		if err != nil {
+			return report, err
+		}
+		if split {
+			half := len(batch) / 2
+			pending = append([][]int{batch[:half], batch[half:]}, pending...)
+		}
+	}
+	return report, nil
+}
+
+// checkPayments validates every payment and checks the client's balance covers their total.
+func (c *ProvenanceClient) checkPayments(ctx context.Context, payments []Payment) error {
+	if len(payments) == 0 {
+		return fmt.Errorf("no payments to make")
+	}
+
+	total := sdk.NewCoins()
+	for i, p := range payments {
+		if _, err := c.ParseAddress(p.Recipient); err != nil {
+			return fmt.Errorf("payment %d: %w", i, err)
+		}
+		if !p.Amount.IsValid() || p.Amount.IsZero() {
+			return fmt.Errorf("payment %d: invalid amount %q", i, p.Amount)
+		}
+		total = total.Add(p.Amount...)
+	}
+
+	for _, need := range total {
+		have, err := c.GetBalance(ctx, c.Address, need.Denom)
+		if err != nil {
+			return fmt.Errorf("error getting %s balance: %w", need.Denom, err)
+		}
+		if have.IsLT(need) {
+			return fmt.Errorf("payout needs %s but %s holds %s", need, c.Address, have)
+		}
+	}
+	return nil
+}
+
+// payBatch pays the payments at indices batch in one transaction and records the outcome in report.
+// It reports whether the batch must be split instead, and returns an error when the payout has to stop.
+func (c *ProvenanceClient) payBatch(ctx context.Context, report *PayoutReport, batch []int, o PayoutOptions) (bool, error) {
+	outputs := make([]banktypes.Output, len(batch))
+	for i, idx := range batch {
+		p := report.Results[idx].Payment
+		outputs[i] = banktypes.Output{Address: p.Recipient, Coins: p.Amount}
+	}
+	msg, err := c.newMultiSend(outputs)
+	if err != nil {
+		return false, err
+	}
+	msgs := []sdk.Msg{msg}
+
+	c.progress().SetCurrent(fmt.Sprintf("paying %d recipients", len(batch)))
+
+	est, err := c.estimateBatch(ctx, msgs, o)
+	switch {
+	case err != nil && (ctx.Err() != nil || isTransportError(err)):
+		return false, fmt.Errorf("error estimating payout gas: %w", err)
+	case err != nil && len(batch) > 1:
+		return true, nil
+	case err != nil:
+		c.recordBatch(report, batch, PaymentFailed, "", 0, err)
+		return false, nil
+	case est.GasLimit > o.MaxGas && len(batch) > 1:
+		return true, nil
+	case est.GasLimit > o.MaxGas:
+		c.recordBatch(report, batch, PaymentFailed, "", 0, fmt.Errorf("needs %d gas, over the %d limit", est.GasLimit, o.MaxGas))
+		return false, nil
+	}
+
+	txOpts := append(append([]TxOption{}, o.TxOptions...), WithGasLimit(est.GasLimit))
+	resp, err := c.SignAndBroadcast(ctx, msgs, txOpts...)
+	if err != nil {
+		// Whether the tx reached the node is unknown, so paying on could pay twice.
+		c.recordBatch(report, batch, PaymentUnknown, "", 0, err)
+		return false, fmt.Errorf("payout stopped: %w", err)
+	}
+	txr := resp.TxResponse
+	if txr == nil {
+		c.recordBatch(report, batch, PaymentUnknown, "", 0, fmt.Errorf("broadcast returned no tx response"))
+		return false, fmt.Errorf("payout stopped: broadcast returned no tx response")
+	}
+	if txr.Code != 0 {
+		c.recordBatch(report, batch, PaymentFailed, txr.TxHash, 0, NewTxFailedError(txr, true))
+		return false, nil
+	}
+
+	res, err := c.WaitOnTx(ctx, txr.TxHash, o.WaitOptions...)
+	if err != nil {
+		c.recordBatch(report, batch, PaymentUnknown, txr.TxHash, 0, err)
+		return false, fmt.Errorf("payout stopped waiting on %s: %w", txr.TxHash, err)
+	}
+	if res.TxResponse.Code != 0 {
+		c.recordBatch(report, batch, PaymentFailed, txr.TxHash, res.TxResponse.Height, NewTxFailedError(res.TxResponse, false))
+		return false, nil
+	}
+	c.recordBatch(report, batch, PaymentSent, txr.TxHash, res.TxResponse.Height, nil)
+	return false, nil
+}
+
+// estimateBatch estimates the gas of msgs at the next sequence. The sequence is reserved while
+// simulating so that concurrent signers on the client do not simulate with it too, then handed back for
+// SignAndBroadcast, which recovers if another signer takes it before the broadcast. A simulation that
+// finds the sequence already taken is retried at the sequence the chain expects.
+func (c *ProvenanceClient) estimateBatch(ctx context.Context, msgs []sdk.Msg, o PayoutOptions) (*FeeEstimate, error) {
+	for attempt := 1; ; attempt++ {
+		seq := c.NextSequence()
+		_, est, err := c.buildTx(ctx, msgs, c.Signer.PubKey(), seq, NewTxOptions(o.TxOptions...))
+		c.ReleaseSequence(seq)
+		if err == nil || !isSequenceMismatch(err) || attempt >= max(c.BroadcastRetryPolicy.MaxAttempts, 1) {
+			return est, err
+		}
+		if _, err := c.resyncSequence(ctx, err.Error()); err != nil {
+			return nil, fmt.Errorf("error resyncing sequence: %w", err)
+		}
+	}
+}
+
+func (c *ProvenanceClient) recordBatch(report *PayoutReport, batch []int, s PaymentStatus, txHash string, height int64, err error) {
+	for _, idx := range batch {
+		res := &report.Results[idx]
+		res.Status = s
+		res.TxHash = txHash
+		res.Height = height
+		if err != nil {
+			res.Error = err.Error()
+		}
+		c.progress().IncrementCount()
+	}
+	c.logger().Info("payout batch",
+		"status", s,
+		"recipients", len(batch),
+		"tx_hash", txHash,
+		"height", height,
+	)
+}
+
+// isTransportError reports whether err is a failure to reach the node rather than a refusal by it.
+func isTransportError(err error) bool {
+	var se interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &se) {
+		return false
+	}
+	switch se.GRPCStatus().Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,223 @@
+package provenance
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cmttypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// payoutNode simulates multi-sends at 1000 gas per output, refusing any that pay blocked, and
+// includes every tx it is sent at height 7.
+type payoutNode struct {
+	*txtypes.UnimplementedServiceServer
+	blocked string
+	balance sdk.Coin
+	// taken is the number of simulations refused because another signer took the sequence.
+	taken int
+
+	mu         sync.Mutex
+	broadcasts [][]banktypes.Output
+}
+
+func (n *payoutNode) outputs(txBz []byte) ([]banktypes.Output, error) {
+	tx, err := NewTxConfig().TxDecoder()(txBz)
+	if err != nil {
+		return nil, err
+	}
+	return tx.GetMsgs()[0].(*banktypes.MsgMultiSend).Outputs, nil
+}
+
+func (n *payoutNode) Simulate(_ context.Context, req *txtypes.SimulateRequest) (*txtypes.SimulateResponse, error) {
+	outs, err := n.outputs(req.TxBytes)
+	if err != nil {
+		return nil, err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.taken > 0 {
+		n.taken--
+		return nil, status.Error(codes.Unknown, "account sequence mismatch, expected 2, got 1: incorrect account sequence")
+	}
+	for _, out := range outs {
+		if out.Address == n.blocked {
+			return nil, status.Errorf(codes.Unknown, "%s is not allowed to receive funds: unauthorized", out.Address)
+		}
+	}
+	return &txtypes.SimulateResponse{GasInfo: &sdk.GasInfo{GasUsed: uint64(1000 * len(outs))}}, nil
+}
+
+func (n *payoutNode) BroadcastTx(_ context.Context, req *txtypes.BroadcastTxRequest) (*txtypes.BroadcastTxResponse, error) {
+	outs, err := n.outputs(req.TxBytes)
+	if err != nil {
+		return nil, err
+	}
+	n.mu.Lock()
+	n.broadcasts = append(n.broadcasts, outs)
+	n.mu.Unlock()
+	return &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: fmt.Sprintf("%X", cmttypes.Tx(req.TxBytes).Hash())}}, nil
+}
+
+func (n *payoutNode) GetTx(_ context.Context, req *txtypes.GetTxRequest) (*txtypes.GetTxResponse, error) {
+	return &txtypes.GetTxResponse{TxResponse: &sdk.TxResponse{TxHash: req.Hash, Height: 7}}, nil
+}
+
+type payoutBank struct {
+	*banktypes.UnimplementedQueryServer
+	node *payoutNode
+}
+
+func (b payoutBank) Balance(_ context.Context, req *banktypes.QueryBalanceRequest) (*banktypes.QueryBalanceResponse, error) {
+	if req.Denom != b.node.balance.Denom {
+		return &banktypes.QueryBalanceResponse{}, nil
+	}
+	return &banktypes.QueryBalanceResponse{Balance: &b.node.balance}, nil
+}
+
+func payoutClient(t *testing.T, n *payoutNode) *ProvenanceClient {
+	t.Helper()
+	conn := serveTestNode(t, func(srv *grpc.Server) {
+		txtypes.RegisterServiceServer(srv, n)
+		banktypes.RegisterQueryServer(srv, payoutBank{node: n})
+	})
+
+	conf := NewLocalnetConfig()
+	signer, err := NewMnemonicSigner(conf, offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cdc, err := NewCodec(conf.AddressPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &ProvenanceClient{
+		Grpc:     conn,
+		BcConfig: conf,
+		Cdc:      cdc,
+		TxConfig: authtx.NewTxConfig(cdc, authtx.DefaultSignModes),
+		Signer:   signer,
+		Sequence: 1,
+	}
+	c.Address, _ = c.FormatAddress(signer.Address())
+	return c
+}
+
+func TestPayout(t *testing.T) {
+	t.Parallel()
+	recipients, err := DeriveAddresses(NewLocalnetConfig(), offlineTestMnemonic, Derivation{Index: 1}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &payoutNode{blocked: recipients[2], balance: sdk.NewInt64Coin("nhash", 1000)}
+	c := payoutClient(t, n)
+
+	var payments []Payment
+	for i, r := range recipients {
+		payments = append(payments, Payment{Recipient: r, Amount: sdk.NewCoins(sdk.NewInt64Coin("nhash", int64(10*(i+1)))), Reference: fmt.Sprintf("inv-%d", i)})
+	}
+
+	// 4 recipients need 6000 gas after adjustment, so the first batch is split in two; the half that
+	// pays the blocked address fails simulation and is split again to isolate it.
+	report, err := c.Payout(context.Background(), payments,
+		WithBatchSize(4),
+		WithMaxGas(3500),
+		WithPayoutWaitOptions(WithPollInterval(10*time.Millisecond)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PaymentStatus{PaymentSent, PaymentSent, PaymentFailed, PaymentSent, PaymentSent}
+	for i, res := range report.Results {
+		if res.Status != want[i] {
+			t.Fatalf("payment %d: %s (%s), want %s", i, res.Status, res.Error, want[i])
+		}
+		if res.Status == PaymentSent && (res.Height != 7 || res.TxHash == "") {
+			t.Fatalf("payment %d: height %d hash %q", i, res.Height, res.TxHash)
+		}
+	}
+	if len(n.broadcasts) != 3 || len(n.broadcasts[0]) != 2 || len(n.broadcasts[1]) != 1 || len(n.broadcasts[2]) != 1 {
+		t.Fatalf("broadcasts: %v", n.broadcasts)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 6 || rows[3][3] != "failed" || rows[1][2] != "inv-0" || rows[1][5] != "7" {
+		t.Fatalf("csv: %v", rows)
+	}
+	buf.Reset()
+	if err := report.WriteJSON(&buf); err != nil || !strings.Contains(buf.String(), `"status": "failed"`) {
+		t.Fatalf("json: %v %s", err, buf.String())
+	}
+}
+
+func TestPayoutChecksBalance(t *testing.T) {
+	t.Parallel()
+	recipients, err := DeriveAddresses(NewLocalnetConfig(), offlineTestMnemonic, Derivation{Index: 1}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &payoutNode{balance: sdk.NewInt64Coin("nhash", 15)}
+	c := payoutClient(t, n)
+
+	payments := []Payment{
+		{Recipient: recipients[0], Amount: sdk.NewCoins(sdk.NewInt64Coin("nhash", 10))},
+		{Recipient: recipients[1], Amount: sdk.NewCoins(sdk.NewInt64Coin("nhash", 10))},
+	}
+	if _, err := c.Payout(context.Background(), payments); err == nil || !strings.Contains(err.Error(), "20nhash") {
+		t.Fatalf("expected an insufficient balance error, got %v", err)
+	}
+	if _, err := c.Payout(context.Background(), []Payment{{Recipient: "nope", Amount: payments[0].Amount}}); err == nil {
+		t.Fatal("expected an invalid recipient error")
+	}
+	if len(n.broadcasts) != 0 {
+		t.Fatalf("broadcast before checks passed: %v", n.broadcasts)
+	}
+}
+
+func TestPayoutSequenceTaken(t *testing.T) {
+	t.Parallel()
+	recipients, err := DeriveAddresses(NewLocalnetConfig(), offlineTestMnemonic, Derivation{Index: 1}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Another signer on the account used sequence 1 after the client last synced.
+	n := &payoutNode{balance: sdk.NewInt64Coin("nhash", 100), taken: 1}
+	c := payoutClient(t, n)
+	c.BroadcastRetryPolicy = DefaultBroadcastRetryPolicy
+
+	payments := []Payment{
+		{Recipient: recipients[0], Amount: sdk.NewCoins(sdk.NewInt64Coin("nhash", 10))},
+		{Recipient: recipients[1], Amount: sdk.NewCoins(sdk.NewInt64Coin("nhash", 10))},
+	}
+	report, err := c.Payout(context.Background(), payments, WithPayoutWaitOptions(WithPollInterval(10*time.Millisecond)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, res := range report.Results {
+		if res.Status != PaymentSent {
+			t.Fatalf("payment %d: %s (%s)", i, res.Status, res.Error)
+		}
+	}
+	if len(n.broadcasts) != 1 || c.Sequence != 3 {
+		t.Fatalf("%d broadcasts, sequence %d", len(n.broadcasts), c.Sequence)
+	}
+}
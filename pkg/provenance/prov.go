@@ -2,20 +2,24 @@ package provenance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 
 	nodetypes "cosmossdk.io/api/cosmos/base/node/v1beta1"
 	tendermint "cosmossdk.io/api/cosmos/base/tendermint/v1beta1"
 	stakingtypes "cosmossdk.io/api/cosmos/staking/v1beta1"
-	"github.com/cosmos/cosmos-sdk/client/tx"
+	"cosmossdk.io/core/address"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
-	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	addresscodec "github.com/cosmos/cosmos-sdk/codec/address"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 
 	/// Blockchain Query Clients
@@ -23,25 +27,55 @@ import (
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	ants "github.com/panjf2000/ants/v2"
 	attrtypes "github.com/provenance-io/provenance/x/attribute/types"
+	flatfees "github.com/provenance-io/provenance/x/flatfees/types"
 	marker "github.com/provenance-io/provenance/x/marker/types"
 	meta "github.com/provenance-io/provenance/x/metadata/types"
+	msgfees "github.com/provenance-io/provenance/x/msgfees/types"
 
 	// Signing packages
-	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	xauthsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 )
 
 type ProvenanceClient struct {
 	Grpc          *GRPCConnection
-	PrivKey       *secp256k1.PrivKey
+	Signer        Signer
 	BcConfig      BlockchainConfigProvider
 	Cdc           *codec.ProtoCodec
+	TxConfig      client.TxConfig
+	AddressCodec  address.Codec
 	Address       string
 	AccountNumber uint64
 	Sequence      uint64
 	Pool          *ants.Pool
 
+	// BroadcastRetryPolicy controls how SignAndBroadcast retries sequence mismatches.
+	BroadcastRetryPolicy RetryPolicy
+
+	// CallPolicy sets the deadline, retries and rate limit of every gRPC call. It is applied when the
+	// client connects, so change it with WithCallPolicy.
+	CallPolicy CallPolicy
+
+	// Logger receives signing, broadcast and wait events. Nil discards them.
+	Logger *slog.Logger
+
+	// Progress receives progress updates from long-running operations. Nil ignores them.
+	Progress Progress
+
+	// heights records the query heights of an AtHeight view.
+	heights *HeightConn
+
+	// accounts holds the named accounts added with AddAccount.
+	accounts *accountRegistry
+
+	// denoms converts amounts between units; see Denoms.
+	denoms *DenomRegistry
+
+	// keystorePath and keystorePassword are set by WithKeystore and opened by NewProvenanceClient.
+	keystorePath     string
+	keystorePassword string
+
 	// Mutex for clients and sequence
 	mu sync.Mutex
 
@@ -49,8 +83,10 @@ type ProvenanceClient struct {
 	authClient       *authtypes.QueryClient
 	attributeClient  *attrtypes.QueryClient
 	bankClient       *banktypes.QueryClient
+	flatFeesClient   *flatfees.QueryClient
 	markerClient     *marker.QueryClient
 	metadataClient   *meta.QueryClient
+	msgFeesClient    *msgfees.QueryClient
 	tendermintClient *tendermint.ServiceClient
 	nodeClient       *nodetypes.ServiceClient
 	stakingClient    *stakingtypes.QueryClient
@@ -66,13 +102,34 @@ func (c *ProvenanceClient) NextSequence() uint64 {
 	return currentSequence
 }
 
-// ResetSequence sets c.Sequence to the on-chain sequence from GetAccountInfo for this client's address.
-// It must not hold c.mu while querying: GetAccountInfo uses AuthClient, which also locks c.mu.
+// ReleaseSequence hands seq back when it was reserved by NextSequence but never broadcast. It only
+// succeeds when seq is the most recently reserved sequence, and reports whether it was released.
+func (c *ProvenanceClient) ReleaseSequence(seq uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Sequence != seq+1 {
+		return false
+	}
+	c.Sequence = seq
+	return true
+}
+
+// ResetSequence is ResetSequenceContext with a background context.
+//
+// Deprecated: use ResetSequenceContext.
 func (c *ProvenanceClient) ResetSequence() (accountNumber uint64, sequence uint64, err error) {
+	return c.ResetSequenceContext(context.Background())
+}
+
+// ResetSequenceContext sets c.Sequence to the on-chain sequence from GetAccountInfoContext for this
+// client's address. It must not hold c.mu while querying: GetAccountInfoContext uses AuthClient, which
+// also locks c.mu.
+func (c *ProvenanceClient) ResetSequenceContext(ctx context.Context) (accountNumber uint64, sequence uint64, err error) {
 	if c.Address == "" {
 		return 0, 0, fmt.Errorf("provenance client has no address")
 	}
-	accountNumber, sequence, err = c.GetAccountInfo(c.Address)
+	accountNumber, sequence, err = c.GetAccountInfoContext(ctx, c.Address)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -82,34 +139,79 @@ func (c *ProvenanceClient) ResetSequence() (accountNumber uint64, sequence uint6
 	return accountNumber, sequence, nil
 }
 
-func NewProvenanceClient(blockchainConfig BlockchainConfigProvider, mnemonicFilePath *string) (*ProvenanceClient, error) {
-	grpc, err := connect(blockchainConfig)
+// ClientOption configures optional ProvenanceClient settings in NewProvenanceClient.
+type ClientOption func(*ProvenanceClient)
+
+// WithSigner signs transactions with s instead of a key derived from a mnemonic file.
+func WithSigner(s Signer) ClientOption {
+	return func(c *ProvenanceClient) {
+		c.Signer = s
+	}
+}
+
+func NewProvenanceClient(blockchainConfig BlockchainConfigProvider, mnemonicFilePath *string, opts ...ClientOption) (*ProvenanceClient, error) {
+	// Each client carries its own bech32 prefixes so clients for different networks can coexist.
+	cdc, err := NewCodec(blockchainConfig.AddressPrefix())
 	if err != nil {
-		return nil, fmt.Errorf("error creating gRPC connection: %w", err)
+		return nil, err
 	}
 
 	config := ProvenanceClient{
-		Grpc:     grpc,
-		BcConfig: blockchainConfig,
-		Cdc:      Codec(),
-		mu:       sync.Mutex{},
+		BcConfig:     blockchainConfig,
+		Cdc:          cdc,
+		AddressCodec: addresscodec.NewBech32Codec(blockchainConfig.AddressPrefix()),
+		mu:           sync.Mutex{},
+
+		BroadcastRetryPolicy: DefaultBroadcastRetryPolicy,
+		CallPolicy:           DefaultCallPolicy,
 	}
 
-	if mnemonicFilePath != nil && strings.TrimSpace(*mnemonicFilePath) != "" {
-		mnemonic, err := ReadMnemonic(*mnemonicFilePath)
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	config.Grpc, err = connect(blockchainConfig, config.CallPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("error creating gRPC connection: %w", err)
+	}
+
+	if config.TxConfig == nil {
+		config.TxConfig, err = newTxConfig(cdc, config.Grpc)
 		if err != nil {
-			return nil, fmt.Errorf("error reading mnemonic: %w", err)
+			return nil, fmt.Errorf("error creating tx config: %w", err)
+		}
+	}
+
+	if mnemonicFilePath != nil && strings.TrimSpace(*mnemonicFilePath) != "" {
+		if config.Signer != nil {
+			return nil, fmt.Errorf("both a mnemonic file and a signer were provided")
 		}
 
-		// Derive the private key from the mnemonic
-		config.PrivKey, err = PrivKeyFromMnemonic(blockchainConfig, *mnemonic)
+		// Derive the signing key from the mnemonic
+		config.Signer, err = NewMnemonicFileSigner(blockchainConfig, *mnemonicFilePath)
 		if err != nil {
 			return nil, fmt.Errorf("error deriving key from mnemonic: %w", err)
 		}
+	}
 
-		address := sdk.AccAddress(config.PrivKey.PubKey().Address()).String()
-		config.Address = address
-		accountNumber, sequence, err := config.ResetSequence()
+	if config.keystorePath != "" {
+		if config.Signer != nil {
+			return nil, fmt.Errorf("a keystore and another signer or mnemonic file were provided")
+		}
+
+		config.Signer, err = NewKeystoreSigner(config.keystorePath, config.keystorePassword)
+		config.keystorePassword = ""
+		if err != nil {
+			return nil, fmt.Errorf("error opening keystore: %w", err)
+		}
+	}
+
+	if config.Signer != nil {
+		config.Address, err = config.FormatAddress(config.Signer.Address())
+		if err != nil {
+			return nil, fmt.Errorf("error encoding signer address: %w", err)
+		}
+		accountNumber, sequence, err := config.ResetSequenceContext(context.Background())
 		if err != nil {
 			return nil, fmt.Errorf("error getting account info: %w", err)
 		}
@@ -133,7 +235,7 @@ func (c *ProvenanceClient) AuthClient() *authtypes.QueryClient {
 	defer c.mu.Unlock()
 
 	if c.authClient == nil {
-		qc := authtypes.NewQueryClient(c.Grpc.Conn)
+		qc := authtypes.NewQueryClient(c.Grpc)
 		c.authClient = &qc
 	}
 	return c.authClient
@@ -145,7 +247,7 @@ func (c *ProvenanceClient) AttributeClient() *attrtypes.QueryClient {
 	defer c.mu.Unlock()
 
 	if c.attributeClient == nil {
-		qc := attrtypes.NewQueryClient(c.Grpc.Conn)
+		qc := attrtypes.NewQueryClient(c.Grpc)
 		c.attributeClient = &qc
 	}
 	return c.attributeClient
@@ -156,19 +258,31 @@ func (c *ProvenanceClient) BankClient() *banktypes.QueryClient {
 	defer c.mu.Unlock()
 
 	if c.bankClient == nil {
-		qc := banktypes.NewQueryClient(c.Grpc.Conn)
+		qc := banktypes.NewQueryClient(c.Grpc)
 		c.bankClient = &qc
 	}
 	return c.bankClient
 }
 
+// Flat fees client
+func (c *ProvenanceClient) FlatFeesClient() *flatfees.QueryClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.flatFeesClient == nil {
+		qc := flatfees.NewQueryClient(c.Grpc)
+		c.flatFeesClient = &qc
+	}
+	return c.flatFeesClient
+}
+
 // Marker client
 func (c *ProvenanceClient) MarkerClient() *marker.QueryClient {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.markerClient == nil {
-		qc := marker.NewQueryClient(c.Grpc.Conn)
+		qc := marker.NewQueryClient(c.Grpc)
 		c.markerClient = &qc
 	}
 	return c.markerClient
@@ -180,19 +294,31 @@ func (c *ProvenanceClient) MetadataClient() *meta.QueryClient {
 	defer c.mu.Unlock()
 
 	if c.metadataClient == nil {
-		qc := meta.NewQueryClient(c.Grpc.Conn)
+		qc := meta.NewQueryClient(c.Grpc)
 		c.metadataClient = &qc
 	}
 	return c.metadataClient
 }
 
+// Msg fees client
+func (c *ProvenanceClient) MsgFeesClient() *msgfees.QueryClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.msgFeesClient == nil {
+		qc := msgfees.NewQueryClient(c.Grpc)
+		c.msgFeesClient = &qc
+	}
+	return c.msgFeesClient
+}
+
 // Tendermint client
 func (c *ProvenanceClient) TendermintClient() *tendermint.ServiceClient {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.tendermintClient == nil {
-		qc := tendermint.NewServiceClient(c.Grpc.Conn)
+		qc := tendermint.NewServiceClient(c.Grpc)
 		c.tendermintClient = &qc
 	}
 	return c.tendermintClient
@@ -204,7 +330,7 @@ func (c *ProvenanceClient) NodeClient() *nodetypes.ServiceClient {
 	defer c.mu.Unlock()
 
 	if c.nodeClient == nil {
-		qc := nodetypes.NewServiceClient(c.Grpc.Conn)
+		qc := nodetypes.NewServiceClient(c.Grpc)
 		c.nodeClient = &qc
 	}
 	return c.nodeClient
@@ -216,15 +342,22 @@ func (c *ProvenanceClient) StakingClient() *stakingtypes.QueryClient {
 	defer c.mu.Unlock()
 
 	if c.stakingClient == nil {
-		qc := stakingtypes.NewQueryClient(c.Grpc.Conn)
+		qc := stakingtypes.NewQueryClient(c.Grpc)
 		c.stakingClient = &qc
 	}
 	return c.stakingClient
 }
 
-// Returns the account number and sequence for the given address
+// GetAccountInfo is GetAccountInfoContext with a background context.
+//
+// Deprecated: use GetAccountInfoContext.
 func (c *ProvenanceClient) GetAccountInfo(address string) (uint64, uint64, error) {
-	res, err := (*c.AuthClient()).Account(context.Background(), &authtypes.QueryAccountRequest{
+	return c.GetAccountInfoContext(context.Background(), address)
+}
+
+// Returns the account number and sequence for the given address
+func (c *ProvenanceClient) GetAccountInfoContext(ctx context.Context, address string) (uint64, uint64, error) {
+	res, err := (*c.AuthClient()).Account(ctx, &authtypes.QueryAccountRequest{
 		Address: address,
 	})
 	if err != nil {
@@ -239,136 +372,165 @@ func (c *ProvenanceClient) GetAccountInfo(address string) (uint64, uint64, error
 	return baseAcc.AccountNumber, baseAcc.Sequence, nil
 }
 
-func (c *ProvenanceClient) SignTx(msg []sdk.Msg, privKeyBytes []byte, accountNumber, sequence uint64, additionalFee int64) ([]byte, error) {
-	ctx := context.Background()
+// SignTx is SignTxContext with a background context.
+//
+// Deprecated: use SignTxContext.
+func (c *ProvenanceClient) SignTx(msg []sdk.Msg, accountNumber, sequence uint64, opts ...TxOption) ([]byte, error) {
+	return c.SignTxContext(context.Background(), msg, accountNumber, sequence, opts...)
+}
+
+// SignTxContext builds a transaction for msgs according to opts, estimates its gas and fee unless they
+// are given explicitly, and signs it with the client's Signer.
+func (c *ProvenanceClient) SignTxContext(ctx context.Context, msg []sdk.Msg, accountNumber, sequence uint64, opts ...TxOption) ([]byte, error) {
+	txBz, err := c.signTx(ctx, msg, accountNumber, sequence, opts...)
+	if errors.Is(err, errFeeEstimate) {
+		// Reset the sequence since we know that this one failed, and will cause downstream failures
+		// in sequence number alignment. This runs even when ctx was cancelled mid-estimate.
+		c.ResetSequenceContext(context.WithoutCancel(ctx))
+	}
+	return txBz, err
+}
+
+// errFeeEstimate marks signTx failures that happened while simulating the transaction.
+var errFeeEstimate = errors.New("fee estimation failed")
 
-	txConfig := NewTxConfig()
+// buildTx builds an unsigned transaction for msgs according to txOpts and sets its estimated gas
+// limit and fee. The estimate accounts for a signature by pubKey at sequence, which is left on the
+// transaction as an empty placeholder; pubKey may be nil only when txOpts sets the gas limit.
+func (c *ProvenanceClient) buildTx(ctx context.Context, msgs []sdk.Msg, pubKey cryptotypes.PubKey, sequence uint64, txOpts TxOptions) (client.TxBuilder, *FeeEstimate, error) {
+	txConfig := c.txConfig()
 
 	// Add the msgs to the tx builder
 	txBuilder := txConfig.NewTxBuilder()
-	if err := txBuilder.SetMsgs(msg...); err != nil {
-		return nil, err
+	if err := txBuilder.SetMsgs(msgs...); err != nil {
+		return nil, nil, err
+	}
+	if err := txOpts.apply(txBuilder, c.addressCodec()); err != nil {
+		return nil, nil, err
 	}
 
-	// Read the private key from the bytes
-	privKey := &secp256k1.PrivKey{Key: privKeyBytes}
-	privs := []cryptotypes.PrivKey{privKey}
-	fmt.Println("Public key:", privKey.PubKey().Address())
-	fmt.Println("Public key string:", sdk.AccAddress(privKey.PubKey().Address()).String())
-
-	// Create a simple signature placeholder
-	// In a real implementation, you would:
-	// 1. Get the sign bytes from the transaction
-	// 2. Sign those bytes with your private key
-	// 3. Create a proper SignatureV2 with the signature and public key
-	var sigs []signing.SignatureV2
-	for _, priv := range privs {
-		sigData := signing.SignatureV2{
-			PubKey: priv.PubKey(), // Set to your public key
-			Data: &signing.SingleSignatureData{
-				SignMode:  signing.SignMode_SIGN_MODE_DIRECT,
-				Signature: nil,
-			},
-			Sequence: sequence,
+	// Set an empty signature so the simulation can account for the signer.
+	if pubKey != nil {
+		if err := txBuilder.SetSignatures(placeholderSignature(pubKey, sequence)); err != nil {
+			return nil, nil, err
 		}
-		sigs = append(sigs, sigData)
 	}
 
-	err := txBuilder.SetSignatures(sigs...)
+	est, err := c.EstimateFeeContext(ctx, txConfig, txBuilder, txOpts)
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("%w: %w", errFeeEstimate, err)
 	}
 
-	feeAmount, _, err := SimulateTx(c.Grpc.Conn, txConfig, txBuilder)
-	if err != nil {
-		// Reset the sequence since we know that this one failed, and will cause downstream failures
-		// in sequence number alignment.
-		c.ResetSequence()
-		return nil, err
-	}
-	fmt.Printf("Fee amount: %d\n", feeAmount)
+	txBuilder.SetGasLimit(est.GasLimit)
+	txBuilder.SetFeeAmount(est.Fee)
+	return txBuilder, est, nil
+}
 
-	// Update the gas limit to be 50% more than the estimated gas
-	fee := sdk.NewCoins(sdk.NewInt64Coin(c.BcConfig.Denom(), int64(feeAmount)+additionalFee))
-	txBuilder.SetFeeAmount(fee)
+// signTx is SignTxContext without the sequence reset, for callers that manage sequences themselves.
+func (c *ProvenanceClient) signTx(ctx context.Context, msg []sdk.Msg, accountNumber, sequence uint64, opts ...TxOption) ([]byte, error) {
+	if c.Signer == nil {
+		return nil, fmt.Errorf("provenance client has no signer")
+	}
 
-	sigs = []signing.SignatureV2{}
-	for _, priv := range privs {
-		signerData := xauthsigning.SignerData{
-			ChainID:       c.BcConfig.ChainID(),
-			AccountNumber: accountNumber,
-			Sequence:      sequence,
-		}
+	txOpts := NewTxOptions(opts...)
+	txConfig := c.txConfig()
+	pubKey := c.Signer.PubKey()
 
-		sigV2, err := tx.SignWithPrivKey(ctx, signing.SignMode_SIGN_MODE_DIRECT, signerData, txBuilder, priv, txConfig, sequence)
+	signerAddress, err := c.FormatAddress(c.Signer.Address())
+	if err != nil {
+		return nil, err
+	}
+	// Only the client's signer signs here, and the chain needs the fee payer's signature too.
+	if txOpts.FeePayer != "" {
+		payer, err := c.ParseAddress(txOpts.FeePayer)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid fee payer: %w", err)
+		}
+		if !payer.Equals(c.Signer.Address()) {
+			return nil, fmt.Errorf("fee payer %s is not the signer %s: build the tx with BuildUnsignedTx and have each signer sign it with SignTxOffline in %s", txOpts.FeePayer, signerAddress, signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON)
 		}
-		sigs = append(sigs, sigV2)
 	}
 
-	// Set the signature
-	if err := txBuilder.SetSignatures(sigs...); err != nil {
+	txBuilder, est, err := c.buildTx(ctx, msg, pubKey, sequence, txOpts)
+	if err != nil {
 		return nil, err
 	}
+	signerData := xauthsigning.SignerData{
+		Address:       signerAddress,
+		ChainID:       c.BcConfig.ChainID(),
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+		PubKey:        pubKey,
+	}
 
-	// Encode the transaction
-	txBz, err := txConfig.TxEncoder()(txBuilder.GetTx())
-	if err != nil {
+	if err := signSingle(ctx, txConfig, txBuilder, c.Signer, signerData, txOpts.SignMode); err != nil {
 		return nil, err
 	}
 
-	// For now, just print the transaction bytes
-	// In a real implementation, you would broadcast this transaction
-	fmt.Printf("Transaction bytes: %x\n", txBz)
-	fmt.Println("Transaction ready for broadcasting")
-
-	txJSONBz, err := txConfig.TxJSONEncoder()(txBuilder.GetTx())
+	// Encode the transaction
+	txBz, err := txConfig.TxEncoder()(txBuilder.GetTx())
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println("Transaction JSON:")
-	txJSON := string(txJSONBz)
-	fmt.Println(txJSON)
+
+	c.logger().Debug("signed tx",
+		"signer", signerData.Address,
+		"sequence", sequence,
+		"msgs", len(msg),
+		"gas_used", est.GasUsed,
+		"gas_limit", est.GasLimit,
+		"fee", est.Fee.String(),
+		"tx_hash", fmt.Sprintf("%X", cmttypes.Tx(txBz).Hash()),
+	)
 
 	return txBz, nil
 }
 
-func (c *ProvenanceClient) BroadcastTx(txBytes []byte) (*txtypes.BroadcastTxResponse, error) {
-	txClient := txtypes.NewServiceClient(c.Grpc.Conn)
+// BroadcastTx is BroadcastTxContext with a background context.
+//
+// Deprecated: use BroadcastTxContext.
+func (c *ProvenanceClient) BroadcastTx(txBytes []byte, opts ...BroadcastOption) (*txtypes.BroadcastTxResponse, error) {
+	return c.BroadcastTxContext(context.Background(), txBytes, opts...)
+}
 
-	resp, err := txClient.BroadcastTx(context.Background(), &txtypes.BroadcastTxRequest{
+// BroadcastTxContext broadcasts a signed transaction. It uses SYNC mode, returning the CheckTx result,
+// unless WithBroadcastMode selects ASYNC, which returns as soon as the node has received the transaction.
+func (c *ProvenanceClient) BroadcastTxContext(ctx context.Context, txBytes []byte, opts ...BroadcastOption) (*txtypes.BroadcastTxResponse, error) {
+	req := &txtypes.BroadcastTxRequest{
 		TxBytes: txBytes,
-		Mode:    txtypes.BroadcastMode_BROADCAST_MODE_SYNC, // or BLOCK/ASYNC
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to broadcast: %w", err)
+		Mode:    txtypes.BroadcastMode_BROADCAST_MODE_SYNC,
 	}
-
-	return resp, nil
-}
-
-// Wait on a broadcasted tx to complete
-func (c *ProvenanceClient) WaitOnTx(txHash string) (*txtypes.GetTxResponse, error) {
-	// return an error if there is no tx hash provided
-	if txHash == "" {
-		return nil, fmt.Errorf("no tx hash provided")
+	for _, opt := range opts {
+		opt(req)
+	}
+	switch req.Mode {
+	case txtypes.BroadcastMode_BROADCAST_MODE_SYNC, txtypes.BroadcastMode_BROADCAST_MODE_ASYNC:
+	default:
+		return nil, fmt.Errorf("unsupported broadcast mode %s", req.Mode)
 	}
 
-	txClient := txtypes.NewServiceClient(c.Grpc.Conn)
+	txClient := txtypes.NewServiceClient(c.Grpc)
+	resp, err := txClient.BroadcastTx(ctx, req)
+	if err != nil {
+		c.logger().Error("broadcast failed", "mode", req.Mode.String(), "error", err)
+		return nil, fmt.Errorf("failed to broadcast: %w", err)
+	}
 
-	// Poll until a tx is indexed or ~20s elapses; transient GetTx RPC errors retry.
-	for i := 0; i < 10; i++ {
-		resp, err := txClient.GetTx(context.Background(), &txtypes.GetTxRequest{
-			Hash: txHash,
-		})
-		if err == nil && resp != nil && resp.TxResponse != nil {
-			return resp, nil
+	if txr := resp.TxResponse; txr != nil {
+		level := slog.LevelInfo
+		if txr.Code != 0 {
+			level = slog.LevelWarn
 		}
-		time.Sleep(2 * time.Second)
+		c.logger().Log(ctx, level, "broadcast tx",
+			"mode", req.Mode.String(),
+			"tx_hash", txr.TxHash,
+			"code", txr.Code,
+			"codespace", txr.Codespace,
+			"raw_log", txr.RawLog,
+		)
 	}
 
-	return nil, fmt.Errorf("get tx: gave up after timeout for hash %s", txHash)
+	return resp, nil
 }
 
 // Context Set Block Height
@@ -376,17 +538,24 @@ func (c *ProvenanceClient) ContextWithBlockHeight(ctx context.Context, height in
 	return metadata.AppendToOutgoingContext(ctx, "x-cosmos-block-height", strconv.FormatInt(height, 10))
 }
 
-func connect(conf BlockchainConfigProvider) (*GRPCConnection, error) {
-	// Internal configuration for the SDK to know what prefix to use for the account and public keys
-	sdkConf := sdk.GetConfig()
-	sdkConf.SetBech32PrefixForAccount(conf.AddressPrefix(), conf.PublicPrefix())
-	sdkConf.SetCoinType(conf.CoinType())
-	sdkConf.Seal()
+func connect(conf BlockchainConfigProvider, policy CallPolicy) (*GRPCConnection, error) {
+	grpcOpts := ConfigGRPCOptions(conf)
+	tlsConf, err := grpcOpts.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
 
-	grpc, err := NewGRPCConnection(conf.URI(), conf.TLS())
+	unary, stream := policy.Interceptors()
+	conn, err := DialEndpoints(ConfigEndpoints(conf), ConnectionOptions{
+		ChainID:            conf.ChainID(),
+		TLSConfig:          tlsConf,
+		DialOptions:        grpcOpts.dialOptions(),
+		UnaryInterceptors:  []grpc.UnaryClientInterceptor{classifyInterceptor, unary},
+		StreamInterceptors: []grpc.StreamClientInterceptor{stream},
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error creating gRPC connection: %w", err)
 	}
 
-	return grpc, nil
+	return conn, nil
 }
@@ -1,27 +1,30 @@
 package provenance
 
 import (
-	"fmt"
+	"context"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/tx"
 	registry "github.com/provenance-io/provenance/x/registry/types"
 )
 
-func (c *ProvenanceClient) RegistryBulkUpdate(entries []registry.RegistryEntry) (*tx.BroadcastTxResponse, error) {
+// RegistryBulkUpdate is RegistryBulkUpdateContext with a background context.
+//
+// Deprecated: use RegistryBulkUpdateContext.
+func (c *ProvenanceClient) RegistryBulkUpdate(entries []registry.RegistryEntry, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
+	return c.RegistryBulkUpdateContext(context.Background(), entries, opts...)
+}
+
+// RegistryBulkUpdateContext writes registry entries in one transaction signed by the client's address.
+func (c *ProvenanceClient) RegistryBulkUpdateContext(ctx context.Context, entries []registry.RegistryEntry, opts ...TxOption) (*tx.BroadcastTxResponse, error) {
 	msg := &registry.MsgRegistryBulkUpdate{
 		Signer:  c.Address,
 		Entries: entries,
 	}
 
-	txBz, err := c.SignTx([]sdk.Msg{msg}, c.PrivKey.Bytes(), c.AccountNumber, c.NextSequence(), 0)
-	if err != nil {
-		return nil, fmt.Errorf("error creating tx: %w", err)
-	}
-
-	resp, err := c.BroadcastTx(txBz)
+	resp, err := c.SignAndBroadcast(ctx, []sdk.Msg{msg}, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("error broadcasting transaction: %w", err)
+		return nil, err
 	}
 
 	return resp, nil
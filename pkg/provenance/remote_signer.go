@@ -0,0 +1,120 @@
+package provenance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// RemoteSigner delegates signing to a signer process reachable over HTTP, typically on a
+// local unix socket or loopback port. The remote side exposes two JSON endpoints:
+//
+//	GET  /pubkey -> {"pub_key": "<base64 compressed secp256k1 key>"}
+//	POST /sign   {"sign_bytes": "<base64>"} -> {"signature": "<base64>"}
+type RemoteSigner struct {
+	baseURL string
+	http    *http.Client
+	pubKey  cryptotypes.PubKey
+}
+
+type remotePubKeyResponse struct {
+	PubKey []byte `json:"pub_key"`
+}
+
+type remoteSignRequest struct {
+	SignBytes []byte `json:"sign_bytes"`
+}
+
+type remoteSignResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// NewRemoteSigner connects to the signer at endpoint and fetches its public key. endpoint is
+// either an http(s) URL or unix:///path/to/socket.
+func NewRemoteSigner(ctx context.Context, endpoint string) (*RemoteSigner, error) {
+	s := &RemoteSigner{
+		baseURL: strings.TrimRight(endpoint, "/"),
+		http:    &http.Client{},
+	}
+
+	if socket, ok := strings.CutPrefix(endpoint, "unix://"); ok {
+		s.baseURL = "http://remote-signer"
+		s.http.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+	}
+
+	var res remotePubKeyResponse
+	if err := s.call(ctx, http.MethodGet, "/pubkey", nil, &res); err != nil {
+		return nil, fmt.Errorf("error fetching remote signer public key: %w", err)
+	}
+	if len(res.PubKey) != secp256k1.PubKeySize {
+		return nil, fmt.Errorf("remote signer returned invalid public key length %d", len(res.PubKey))
+	}
+	s.pubKey = &secp256k1.PubKey{Key: res.PubKey}
+
+	return s, nil
+}
+
+func (s *RemoteSigner) Address() sdk.AccAddress {
+	return sdk.AccAddress(s.pubKey.Address())
+}
+
+func (s *RemoteSigner) PubKey() cryptotypes.PubKey {
+	return s.pubKey
+}
+
+func (s *RemoteSigner) Sign(ctx context.Context, signBytes []byte) ([]byte, error) {
+	var res remoteSignResponse
+	if err := s.call(ctx, http.MethodPost, "/sign", remoteSignRequest{SignBytes: signBytes}, &res); err != nil {
+		return nil, fmt.Errorf("remote sign failed: %w", err)
+	}
+	if len(res.Signature) == 0 {
+		return nil, fmt.Errorf("remote signer returned an empty signature")
+	}
+	return res.Signature, nil
+}
+
+func (s *RemoteSigner) call(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		bz, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(bz)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
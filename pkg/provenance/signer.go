@@ -0,0 +1,152 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// Signer signs transaction sign bytes on behalf of a single account. Implementations keep
+// the private key material to themselves, so application code only ever handles the
+// address, the public key and the resulting signatures.
+type Signer interface {
+	// Address returns the account address controlled by this signer.
+	Address() sdk.AccAddress
+
+	// PubKey returns the public key that verifies this signer's signatures.
+	PubKey() cryptotypes.PubKey
+
+	// Sign returns a signature over signBytes.
+	Sign(ctx context.Context, signBytes []byte) ([]byte, error)
+}
+
+// SignModeSigner is a Signer that needs to know the sign mode of the bytes it signs, such as a
+// keyring holding Ledger keys, which only sign SIGN_MODE_LEGACY_AMINO_JSON. Transactions are signed
+// with SignInMode when a signer implements it.
+type SignModeSigner interface {
+	Signer
+
+	// SignInMode returns a signature over signBytes, which are in mode.
+	SignInMode(ctx context.Context, signBytes []byte, mode signing.SignMode) ([]byte, error)
+}
+
+// Verify that the built-in signers implement the Signer interface
+var (
+	_ Signer         = (*MnemonicSigner)(nil)
+	_ SignModeSigner = (*KeyringSigner)(nil)
+	_ Signer         = (*RemoteSigner)(nil)
+)
+
+// signInMode signs signBytes, which are in mode, with SignInMode when signer implements it.
+func signInMode(ctx context.Context, signer Signer, signBytes []byte, mode signing.SignMode) ([]byte, error) {
+	if s, ok := signer.(SignModeSigner); ok {
+		return s.SignInMode(ctx, signBytes, mode)
+	}
+	return signer.Sign(ctx, signBytes)
+}
+
+// MnemonicSigner holds a secp256k1 key in memory, derived from a mnemonic or read from a keystore.
+type MnemonicSigner struct {
+	priv *secp256k1.PrivKey
+}
+
+// NewMnemonicSigner derives the signing key for the configured coin type from mnemonic.
+func NewMnemonicSigner(conf BlockchainConfigProvider, mnemonic string) (*MnemonicSigner, error) {
+	return NewDerivedSigner(conf, mnemonic, Derivation{})
+}
+
+// NewDerivedSigner derives the signing key d selects for the configured coin type from mnemonic.
+func NewDerivedSigner(conf BlockchainConfigProvider, mnemonic string, d Derivation) (*MnemonicSigner, error) {
+	priv, err := DerivePrivKey(conf, mnemonic, d)
+	if err != nil {
+		return nil, err
+	}
+	return &MnemonicSigner{priv: priv}, nil
+}
+
+// NewMnemonicFileSigner reads a mnemonic from path and derives the signing key from it.
+func NewMnemonicFileSigner(conf BlockchainConfigProvider, path string) (*MnemonicSigner, error) {
+	mnemonic, err := ReadMnemonic(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading mnemonic: %w", err)
+	}
+	return NewMnemonicSigner(conf, *mnemonic)
+}
+
+func (s *MnemonicSigner) Address() sdk.AccAddress {
+	return sdk.AccAddress(s.priv.PubKey().Address())
+}
+
+func (s *MnemonicSigner) PubKey() cryptotypes.PubKey {
+	return s.priv.PubKey()
+}
+
+func (s *MnemonicSigner) Sign(_ context.Context, signBytes []byte) ([]byte, error) {
+	return s.priv.Sign(signBytes)
+}
+
+// KeyringSigner signs with a key stored in a Cosmos SDK keyring.
+type KeyringSigner struct {
+	kr      keyring.Keyring
+	uid     string
+	pubKey  cryptotypes.PubKey
+	address sdk.AccAddress
+}
+
+// OpenKeyring opens a Cosmos SDK keyring rooted at rootDir. backend is one of the keyring
+// backends, usually keyring.BackendFile or keyring.BackendTest. userInput supplies the
+// passphrase for the file backend and may be nil for the test backend.
+func OpenKeyring(appName, backend, rootDir string, userInput io.Reader) (keyring.Keyring, error) {
+	kr, err := keyring.New(appName, backend, rootDir, userInput, Codec())
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s keyring: %w", backend, err)
+	}
+	return kr, nil
+}
+
+// NewKeyringSigner returns a signer for the key named uid in kr.
+func NewKeyringSigner(kr keyring.Keyring, uid string) (*KeyringSigner, error) {
+	record, err := kr.Key(uid)
+	if err != nil {
+		return nil, fmt.Errorf("error loading key %q: %w", uid, err)
+	}
+
+	pubKey, err := record.GetPubKey()
+	if err != nil {
+		return nil, fmt.Errorf("error reading public key for %q: %w", uid, err)
+	}
+
+	return &KeyringSigner{
+		kr:      kr,
+		uid:     uid,
+		pubKey:  pubKey,
+		address: sdk.AccAddress(pubKey.Address()),
+	}, nil
+}
+
+func (s *KeyringSigner) Address() sdk.AccAddress {
+	return s.address
+}
+
+func (s *KeyringSigner) PubKey() cryptotypes.PubKey {
+	return s.pubKey
+}
+
+// Sign signs signBytes as SIGN_MODE_DIRECT bytes.
+func (s *KeyringSigner) Sign(ctx context.Context, signBytes []byte) ([]byte, error) {
+	return s.SignInMode(ctx, signBytes, signing.SignMode_SIGN_MODE_DIRECT)
+}
+
+func (s *KeyringSigner) SignInMode(_ context.Context, signBytes []byte, mode signing.SignMode) ([]byte, error) {
+	sig, _, err := s.kr.Sign(s.uid, signBytes, mode)
+	if err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
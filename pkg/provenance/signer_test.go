@@ -0,0 +1,180 @@
+package provenance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// verifySigner checks that s signs and that its signatures verify against its PubKey.
+func verifySigner(t *testing.T, s Signer) {
+	t.Helper()
+	msg := []byte("sign bytes")
+	sig, err := s.Sign(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if !s.PubKey().VerifySignature(msg, sig) {
+		t.Fatal("signature does not verify against the signer's public key")
+	}
+	if !s.Address().Equals(sdk.AccAddress(s.PubKey().Address())) {
+		t.Fatalf("address %s is not the public key's %s", s.Address(), s.PubKey().Address())
+	}
+}
+
+func TestMnemonicSigner(t *testing.T) {
+	t.Parallel()
+	s, err := NewMnemonicSigner(NewLocalnetConfig(), offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifySigner(t, s)
+}
+
+func TestKeyringSigner(t *testing.T) {
+	t.Parallel()
+	kr := keyring.NewInMemory(Codec())
+	if _, err := kr.NewAccount("ops", offlineTestMnemonic, "", hd.CreateHDPath(505, 0, 0).String(), hd.Secp256k1); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewKeyringSigner(kr, "ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifySigner(t, s)
+
+	if _, err := NewKeyringSigner(kr, "missing"); err == nil {
+		t.Fatal("expected an error for a missing key")
+	}
+
+	// The tx's sign mode reaches the keyring, as Ledger keys need it to be amino JSON.
+	rec := &modeRecordingKeyring{Keyring: kr}
+	s, err = NewKeyringSigner(rec, "ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := NewLocalnetConfig()
+	params := OfflineSignParams{ChainID: conf.ChainID(), AddressPrefix: conf.AddressPrefix(), SignMode: signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON}
+	if _, err := SignTxOffline(context.Background(), s, unsignedSend(t, conf, s.Address()), params); err != nil {
+		t.Fatal(err)
+	}
+	if rec.mode != signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON {
+		t.Fatalf("keyring asked to sign in %s", rec.mode)
+	}
+}
+
+// modeRecordingKeyring records the sign mode it was last asked to sign in.
+type modeRecordingKeyring struct {
+	keyring.Keyring
+	mode signing.SignMode
+}
+
+func (k *modeRecordingKeyring) Sign(uid string, msg []byte, mode signing.SignMode) ([]byte, cryptotypes.PubKey, error) {
+	k.mode = mode
+	return k.Keyring.Sign(uid, msg, mode)
+}
+
+// remoteSignerServer serves priv's public key and answers /sign with sign.
+func remoteSignerServer(t *testing.T, priv *secp256k1.PrivKey, sign http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /pubkey", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(remotePubKeyResponse{PubKey: priv.PubKey().Bytes()})
+	})
+	mux.HandleFunc("POST /sign", sign)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRemoteSigner(t *testing.T) {
+	t.Parallel()
+	priv := secp256k1.GenPrivKey()
+	ctx := context.Background()
+
+	srv := remoteSignerServer(t, priv, func(w http.ResponseWriter, r *http.Request) {
+		var req remoteSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sig, err := priv.Sign(req.SignBytes)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(remoteSignResponse{Signature: sig})
+	})
+	s, err := NewRemoteSigner(ctx, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.PubKey().Equals(priv.PubKey()) {
+		t.Fatal("remote signer has the wrong public key")
+	}
+	verifySigner(t, s)
+
+	failing := map[string]http.HandlerFunc{
+		"status": func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "key locked", http.StatusForbidden)
+		},
+		"malformed body": func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte(`{"signature": `))
+		},
+		"empty signature": func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte(`{}`))
+		},
+	}
+	for name, handler := range failing {
+		s, err := NewRemoteSigner(ctx, remoteSignerServer(t, priv, handler).URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Sign(ctx, []byte("sign bytes")); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRemoteSignerContext(t *testing.T) {
+	t.Parallel()
+	priv := secp256k1.GenPrivKey()
+	approved := make(chan struct{})
+	srv := remoteSignerServer(t, priv, func(http.ResponseWriter, *http.Request) {
+		// Wait for an approval that only comes once the test is over.
+		<-approved
+	})
+	t.Cleanup(func() { close(approved) })
+	s, err := NewRemoteSigner(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.Sign(ctx, []byte("sign bytes")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRemoteSignerBadPubKey(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(remotePubKeyResponse{PubKey: []byte{1, 2, 3}})
+	}))
+	t.Cleanup(srv.Close)
+	if _, err := NewRemoteSigner(context.Background(), srv.URL); err == nil {
+		t.Fatal("expected an error for an invalid public key")
+	}
+}
@@ -0,0 +1,155 @@
+package provenance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	txsigning "cosmossdk.io/x/tx/signing"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	xauthsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	marker "github.com/provenance-io/provenance/x/marker/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// noMetadataConn answers every denom metadata query with NotFound, as a node does for denoms without any.
+type noMetadataConn struct{}
+
+func (noMetadataConn) Invoke(context.Context, string, any, any, ...grpc.CallOption) error {
+	return status.Error(codes.NotFound, "no metadata")
+}
+
+func (noMetadataConn) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Error(codes.Unimplemented, "no streams")
+}
+
+func TestSignModesVerify(t *testing.T) {
+	t.Parallel()
+	conf := NewLocalnetConfig()
+	signer, err := NewMnemonicSigner(conf, offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cdc, err := NewCodec(conf.AddressPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	txConfig, err := newTxConfig(cdc, noMetadataConn{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := cdc.InterfaceRegistry().SigningContext().AddressCodec().BytesToString(signer.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A marker msg run through authz covers provenance messages nested in an Any.
+	coin := sdk.NewInt64Coin("nhash", 5)
+	exec := authz.NewMsgExec(signer.Address(), []sdk.Msg{&marker.MsgMintRequest{Amount: coin, Administrator: from}})
+	exec.Grantee = from
+	msgs := []sdk.Msg{&banktypes.MsgSend{FromAddress: from, ToAddress: from, Amount: sdk.NewCoins(coin)}, &exec}
+
+	pkAny, err := codectypes.NewAnyWithValue(signer.PubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mode := range []signing.SignMode{
+		signing.SignMode_SIGN_MODE_DIRECT,
+		signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON,
+		signing.SignMode_SIGN_MODE_TEXTUAL,
+	} {
+		b := txConfig.NewTxBuilder()
+		if err := b.SetMsgs(msgs...); err != nil {
+			t.Fatal(err)
+		}
+		b.SetGasLimit(200000)
+		b.SetFeeAmount(sdk.NewCoins(coin))
+
+		data := xauthsigning.SignerData{Address: from, ChainID: conf.ChainID(), AccountNumber: 3, Sequence: 9, PubKey: signer.PubKey()}
+		if err := signSingle(context.Background(), txConfig, b, signer, data, mode); err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+
+		// Check the signature the way the ante handler does.
+		sigs, err := b.GetTx().GetSignaturesV2()
+		if err != nil || len(sigs) != 1 {
+			t.Fatalf("%s: signatures %v %v", mode, sigs, err)
+		}
+		if got := sigs[0].Data.(*signing.SingleSignatureData).SignMode; got != mode {
+			t.Fatalf("%s: signed in %s", mode, got)
+		}
+		txData := b.GetTx().(xauthsigning.V2AdaptableTx).GetSigningTxData()
+		err = xauthsigning.VerifySignature(context.Background(), signer.PubKey(), txsigning.SignerData{
+			Address:       from,
+			ChainID:       data.ChainID,
+			AccountNumber: data.AccountNumber,
+			Sequence:      data.Sequence,
+			PubKey:        &anypb.Any{TypeUrl: pkAny.TypeUrl, Value: pkAny.Value},
+		}, sigs[0].Data, txConfig.SignModeHandler(), txData)
+		if err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+	}
+}
+
+func TestSignTxOfflineRejectsTextual(t *testing.T) {
+	t.Parallel()
+	conf := NewLocalnetConfig()
+	signer, err := NewMnemonicSigner(conf, offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := OfflineSignParams{ChainID: conf.ChainID(), AddressPrefix: conf.AddressPrefix(), SignMode: signing.SignMode_SIGN_MODE_TEXTUAL}
+	if _, err := SignTxOffline(context.Background(), signer, unsignedSend(t, conf, signer.Address()), params); err == nil {
+		t.Fatal("expected an error signing textual offline")
+	}
+}
+
+func TestLegacyAmino(t *testing.T) {
+	t.Parallel()
+	cdc := LegacyAmino()
+	coin := sdk.NewInt64Coin("nhash", 5)
+	bz, err := cdc.MarshalJSON(&marker.MsgMintRequest{Amount: coin, Administrator: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bz), `"type":"/provenance.marker.v1.MsgMintRequest"`) {
+		t.Fatalf("marker msg: %s", bz)
+	}
+	bz, err = cdc.MarshalJSON(&banktypes.MsgSend{Amount: sdk.NewCoins(coin)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bz), `"type":"cosmos-sdk/MsgSend"`) {
+		t.Fatalf("bank msg: %s", bz)
+	}
+
+	// Multisig keys round-trip in the format older tools read.
+	signer, err := NewMnemonicSigner(NewLocalnetConfig(), offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := NewMultisigPubKey(1, []cryptotypes.PubKey{signer.PubKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bz, err = cdc.MarshalJSON(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded cryptotypes.PubKey
+	if err := cdc.UnmarshalJSON(bz, &decoded); err != nil || !decoded.Equals(key) {
+		t.Fatalf("multisig key %s: %v", bz, err)
+	}
+	if !strings.Contains(string(bz), `"type":"tendermint/PubKeyMultisigThreshold"`) {
+		t.Fatalf("multisig key: %s", bz)
+	}
+}
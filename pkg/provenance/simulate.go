@@ -10,8 +10,15 @@ import (
 	"google.golang.org/grpc"
 )
 
-// SimulateTx returns the estimated fee and gas for the transaction
-func SimulateTx(grpcConn *grpc.ClientConn, txConfig client.TxConfig, txBuilder client.TxBuilder) (uint64, uint64, error) {
+// SimulateTx is SimulateTxContext with a background context.
+//
+// Deprecated: use SimulateTxContext.
+func SimulateTx(grpcConn grpc.ClientConnInterface, txConfig client.TxConfig, txBuilder client.TxBuilder) (uint64, uint64, error) {
+	return SimulateTxContext(context.Background(), grpcConn, txConfig, txBuilder)
+}
+
+// SimulateTxContext returns the estimated fee and gas for the transaction
+func SimulateTxContext(ctx context.Context, grpcConn grpc.ClientConnInterface, txConfig client.TxConfig, txBuilder client.TxBuilder) (uint64, uint64, error) {
 	txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to encode tx: %w", err)
@@ -19,7 +26,7 @@ func SimulateTx(grpcConn *grpc.ClientConn, txConfig client.TxConfig, txBuilder c
 
 	txClient := txtypes.NewServiceClient(grpcConn)
 
-	resp, err := txClient.Simulate(context.Background(), &txtypes.SimulateRequest{
+	resp, err := txClient.Simulate(ctx, &txtypes.SimulateRequest{
 		TxBytes: txBytes,
 	})
 	if err != nil {
@@ -0,0 +1,161 @@
+package provenance
+
+import (
+	"fmt"
+
+	"cosmossdk.io/core/address"
+	"github.com/cosmos/cosmos-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// DefaultGasAdjustment is the multiplier applied to simulated gas when no explicit gas limit is given.
+// Simulation is not exact, so the limit leaves 50% headroom over the simulated gas used.
+const DefaultGasAdjustment = 1.5
+
+// TxOptions controls how SignTx builds a transaction. Build it with TxOption values rather than
+// filling it in directly so that defaults are applied consistently.
+type TxOptions struct {
+	// Memo is attached to the transaction as-is.
+	Memo string
+
+	// TimeoutHeight is the block height after which the transaction can no longer be included. 0 disables it.
+	TimeoutHeight uint64
+
+	// GasLimit skips simulation and uses this gas limit when non-zero.
+	GasLimit uint64
+
+	// GasAdjustment multiplies the simulated gas used to produce the gas limit.
+	GasAdjustment float64
+
+	// FeeGranter is the bech32 address of an account that granted the signer a fee allowance.
+	FeeGranter string
+
+	// FeePayer is the bech32 address of the account paying the fee. It must also sign the transaction,
+	// so SignTx and SignAndBroadcast only accept the client's own address; for another payer build the
+	// transaction with BuildUnsignedTx and have each signer sign it with SignTxOffline in
+	// SIGN_MODE_LEGACY_AMINO_JSON.
+	FeePayer string
+
+	// FixedFee, when set, is used as the fee instead of the estimated one.
+	FixedFee sdk.Coins
+
+	// AdditionalFee is added on top of the estimated fee.
+	AdditionalFee sdk.Coins
+
+	// SignMode is the mode the signer signs in. SIGN_MODE_UNSPECIFIED means SIGN_MODE_DIRECT.
+	SignMode signing.SignMode
+}
+
+// TxOption configures a single TxOptions field.
+type TxOption func(*TxOptions)
+
+// NewTxOptions returns TxOptions with defaults applied, followed by opts in order.
+func NewTxOptions(opts ...TxOption) TxOptions {
+	o := TxOptions{
+		GasAdjustment: DefaultGasAdjustment,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func WithMemo(memo string) TxOption {
+	return func(o *TxOptions) {
+		o.Memo = memo
+	}
+}
+
+func WithTimeoutHeight(height uint64) TxOption {
+	return func(o *TxOptions) {
+		o.TimeoutHeight = height
+	}
+}
+
+func WithGasLimit(limit uint64) TxOption {
+	return func(o *TxOptions) {
+		o.GasLimit = limit
+	}
+}
+
+func WithGasAdjustment(adjustment float64) TxOption {
+	return func(o *TxOptions) {
+		o.GasAdjustment = adjustment
+	}
+}
+
+func WithFeeGranter(granter string) TxOption {
+	return func(o *TxOptions) {
+		o.FeeGranter = granter
+	}
+}
+
+func WithFeePayer(payer string) TxOption {
+	return func(o *TxOptions) {
+		o.FeePayer = payer
+	}
+}
+
+func WithFixedFee(fee sdk.Coins) TxOption {
+	return func(o *TxOptions) {
+		o.FixedFee = fee
+	}
+}
+
+func WithAdditionalFee(fee sdk.Coins) TxOption {
+	return func(o *TxOptions) {
+		o.AdditionalFee = fee
+	}
+}
+
+// WithSignMode signs in mode instead of SIGN_MODE_DIRECT. SIGN_MODE_LEGACY_AMINO_JSON signs the
+// JSON that hardware wallets and legacy amino multisigs display and verify; SIGN_MODE_TEXTUAL signs a
+// human-readable rendering of the transaction.
+func WithSignMode(mode signing.SignMode) TxOption {
+	return func(o *TxOptions) {
+		o.SignMode = mode
+	}
+}
+
+// apply sets the memo, timeout height, fee granter and fee payer on txBuilder. Gas and fee
+// amounts are left to SignTx since they may depend on simulation. Fee addresses must use the
+// prefix of ac.
+func (o TxOptions) apply(txBuilder client.TxBuilder, ac address.Codec) error {
+	if o.GasAdjustment <= 0 {
+		return fmt.Errorf("gas adjustment must be positive, got %v", o.GasAdjustment)
+	}
+
+	txBuilder.SetMemo(o.Memo)
+	txBuilder.SetTimeoutHeight(o.TimeoutHeight)
+
+	if o.FeeGranter != "" {
+		granter, err := ac.StringToBytes(o.FeeGranter)
+		if err != nil {
+			return fmt.Errorf("invalid fee granter %q: %w", o.FeeGranter, err)
+		}
+		txBuilder.SetFeeGranter(granter)
+	}
+
+	if o.FeePayer != "" {
+		payer, err := ac.StringToBytes(o.FeePayer)
+		if err != nil {
+			return fmt.Errorf("invalid fee payer %q: %w", o.FeePayer, err)
+		}
+		txBuilder.SetFeePayer(payer)
+	}
+
+	// The builder formats fee addresses with the global SDK prefix, so put back the ones we were given.
+	if o.FeeGranter != "" || o.FeePayer != "" {
+		protoTx, ok := txBuilder.(interface{ GetProtoTx() *txtypes.Tx })
+		if !ok {
+			return fmt.Errorf("tx builder %T does not expose its fee", txBuilder)
+		}
+		fee := protoTx.GetProtoTx().AuthInfo.Fee
+		fee.Granter = o.FeeGranter
+		fee.Payer = o.FeePayer
+	}
+
+	return nil
+}
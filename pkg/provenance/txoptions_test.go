@@ -0,0 +1,72 @@
+package provenance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	addresscodec "github.com/cosmos/cosmos-sdk/codec/address"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+func TestNewTxOptions(t *testing.T) {
+	t.Parallel()
+	o := NewTxOptions()
+	if o.GasAdjustment != DefaultGasAdjustment || o.Memo != "" || o.GasLimit != 0 || o.FixedFee != nil {
+		t.Fatalf("unexpected defaults %+v", o)
+	}
+
+	fee := sdk.NewCoins(sdk.NewInt64Coin("nhash", 10))
+	o = NewTxOptions(WithMemo("first"), WithTimeoutHeight(90), WithGasLimit(5000), WithGasAdjustment(2), WithFixedFee(fee), WithMemo("last"))
+	if o.Memo != "last" || o.TimeoutHeight != 90 || o.GasLimit != 5000 || o.GasAdjustment != 2 || !o.FixedFee.Equal(fee) {
+		t.Fatalf("unexpected options %+v", o)
+	}
+}
+
+func TestTxOptionsApply(t *testing.T) {
+	t.Parallel()
+	ac := addresscodec.NewBech32Codec("tp")
+	addr, _ := ac.BytesToString(make([]byte, 20))
+	other, _ := addresscodec.NewBech32Codec("pb").BytesToString(make([]byte, 20))
+
+	txConfig := NewTxConfig()
+	txBuilder := txConfig.NewTxBuilder()
+	if err := NewTxOptions(WithMemo("hello"), WithTimeoutHeight(42), WithFeeGranter(addr)).apply(txBuilder, ac); err != nil {
+		t.Fatal(err)
+	}
+	tx := txBuilder.GetTx()
+	if tx.GetMemo() != "hello" || tx.GetTimeoutHeight() != 42 {
+		t.Fatalf("memo %q, timeout height %d", tx.GetMemo(), tx.GetTimeoutHeight())
+	}
+	if fee := txBuilder.(interface{ GetProtoTx() *txtypes.Tx }).GetProtoTx().AuthInfo.Fee; fee.Granter != addr || fee.Payer != "" {
+		t.Fatalf("fee granter %q payer %q", fee.Granter, fee.Payer)
+	}
+
+	for name, opts := range map[string]TxOptions{
+		"granter prefix":  NewTxOptions(WithFeeGranter(other)),
+		"payer prefix":    NewTxOptions(WithFeePayer(other)),
+		"payer malformed": NewTxOptions(WithFeePayer("tp1notanaddress")),
+		"gas adjustment":  NewTxOptions(WithGasAdjustment(0)),
+	} {
+		if err := opts.apply(txConfig.NewTxBuilder(), ac); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSignTxRejectsOtherFeePayer(t *testing.T) {
+	t.Parallel()
+	conf := NewLocalnetConfig()
+	signer, err := NewMnemonicSigner(conf, offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &ProvenanceClient{BcConfig: conf, Signer: signer}
+	payer, _ := c.FormatAddress(append(make([]byte, 19), 1))
+
+	_, err = c.signTx(context.Background(), nil, 0, 0, WithFeePayer(payer))
+	if err == nil || !strings.Contains(err.Error(), "SignTxOffline in SIGN_MODE_LEGACY_AMINO_JSON") {
+		t.Fatalf("got %v, want an error pointing to SignTxOffline in amino JSON", err)
+	}
+}
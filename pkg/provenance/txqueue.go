@@ -0,0 +1,385 @@
+package provenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	cmttypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrTxQueueClosed is the result of a submission made after TxQueue.Close, or still pending when
+// Close gave up waiting for the queue to drain.
+var ErrTxQueueClosed = errors.New("tx queue closed")
+
+// TxQueueOptions tunes a TxQueue. Zero fields fall back to DefaultTxQueueOptions.
+type TxQueueOptions struct {
+	// MaxInFlight is the number of broadcast transactions allowed to await inclusion at once.
+	MaxInFlight int
+	// PollInterval is how often in-flight transactions are checked for inclusion.
+	PollInterval time.Duration
+	// InclusionTimeout is how long a broadcast transaction may go unseen before it is considered
+	// dropped, along with every transaction sent after it.
+	InclusionTimeout time.Duration
+	// Buffer is the number of submissions accepted without blocking Submit.
+	Buffer int
+}
+
+var DefaultTxQueueOptions = TxQueueOptions{
+	MaxInFlight:      20,
+	PollInterval:     time.Second,
+	InclusionTimeout: time.Minute,
+	Buffer:           100,
+}
+
+// TxFuture is the eventual result of a transaction submitted to a TxQueue.
+type TxFuture struct {
+	done chan struct{}
+	resp *sdk.TxResponse
+	err  error
+}
+
+func newTxFuture() *TxFuture {
+	return &TxFuture{done: make(chan struct{})}
+}
+
+func (f *TxFuture) complete(resp *sdk.TxResponse, err error) {
+	f.resp = resp
+	f.err = err
+	close(f.done)
+}
+
+// Done is closed once the transaction has been included in a block or has failed.
+func (f *TxFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the transaction resolves or ctx is done. The response is returned whenever the
+// chain produced one, including alongside the error of a transaction that failed with a non-zero code.
+func (f *TxFuture) Wait(ctx context.Context) (*sdk.TxResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-f.done:
+		return f.resp, f.err
+	}
+}
+
+type queuedTx struct {
+	msgs     []sdk.Msg
+	opts     []TxOption
+	future   *TxFuture
+	attempts int
+
+	// Set once the tx has been broadcast.
+	seq    uint64
+	hash   string
+	sentAt time.Time
+}
+
+// TxQueue submits transactions for the client's signer in order. Transactions are signed with
+// consecutive sequences and broadcast without waiting for the previous one to be included, up to
+// TxQueueOptions.MaxInFlight at a time. When a sequence goes wrong, either because CheckTx reports a
+// mismatch or because an in-flight transaction is never included, the queue waits for the remaining
+// in-flight transactions to settle, resyncs the sequence from the chain and re-queues every
+// transaction that did not make it, in their original order.
+//
+// A TxQueue owns the client's sequence while it is open, so other signing paths on the same client
+// should not be used concurrently.
+type TxQueue struct {
+	c        *ProvenanceClient
+	opts     TxQueueOptions
+	ctx      context.Context
+	cancel   context.CancelFunc
+	submit   chan *queuedTx
+	closing  chan struct{}
+	abort    chan struct{}
+	finished chan struct{}
+
+	mu        sync.RWMutex
+	closed    bool
+	abortOnce sync.Once
+
+	// Owned by run.
+	pending   []*queuedTx
+	inflight  []*queuedTx
+	resyncing bool
+}
+
+// NewTxQueue starts a TxQueue for the client's signer.
+func (c *ProvenanceClient) NewTxQueue(opts TxQueueOptions) *TxQueue {
+	if opts.MaxInFlight <= 0 {
+		opts.MaxInFlight = DefaultTxQueueOptions.MaxInFlight
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultTxQueueOptions.PollInterval
+	}
+	if opts.InclusionTimeout <= 0 {
+		opts.InclusionTimeout = DefaultTxQueueOptions.InclusionTimeout
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultTxQueueOptions.Buffer
+	}
+
+	q := &TxQueue{
+		c:        c,
+		opts:     opts,
+		submit:   make(chan *queuedTx, opts.Buffer),
+		closing:  make(chan struct{}),
+		abort:    make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	// Chain calls are cancelled when Close gives up on the queue.
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	go q.run()
+	return q
+}
+
+// Submit queues msgs to be signed and broadcast as one transaction.
+func (q *TxQueue) Submit(msgs []sdk.Msg, opts ...TxOption) *TxFuture {
+	f := newTxFuture()
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		f.complete(nil, ErrTxQueueClosed)
+		return f
+	}
+	q.submit <- &queuedTx{msgs: msgs, opts: opts, future: f}
+	return f
+}
+
+// Close stops accepting submissions and waits for every queued transaction to be included or to
+// fail. If ctx is done first, the remaining transactions fail with ErrTxQueueClosed and ctx.Err()
+// is returned; transactions already broadcast may still be included.
+func (q *TxQueue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.closing)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.finished:
+		return nil
+	case <-ctx.Done():
+		q.abortOnce.Do(func() {
+			q.cancel()
+			close(q.abort)
+		})
+		<-q.finished
+		return ctx.Err()
+	}
+}
+
+func (q *TxQueue) run() {
+	defer close(q.finished)
+	defer q.cancel()
+
+	ticker := time.NewTicker(q.opts.PollInterval)
+	defer ticker.Stop()
+
+	closing := q.closing
+	draining := false
+	for {
+		for len(q.pending) > 0 && len(q.inflight) < q.opts.MaxInFlight {
+			if !q.sendNext() {
+				break
+			}
+		}
+
+		if draining && len(q.pending) == 0 && len(q.inflight) == 0 {
+			return
+		}
+
+		select {
+		case tx := <-q.submit:
+			q.pending = append(q.pending, tx)
+		case <-closing:
+			// Submit can no longer send, so whatever is buffered is all that is left.
+			for len(q.submit) > 0 {
+				q.pending = append(q.pending, <-q.submit)
+			}
+			closing = nil
+			draining = true
+		case <-q.abort:
+			for _, tx := range append(q.inflight, q.pending...) {
+				tx.future.complete(nil, ErrTxQueueClosed)
+			}
+			return
+		case <-ticker.C:
+			q.poll()
+		}
+	}
+}
+
+// sendNext signs and broadcasts the head of the pending queue. It returns false when nothing can be
+// sent until in-flight transactions settle.
+func (q *TxQueue) sendNext() bool {
+	c := q.c
+
+	if q.resyncing {
+		if len(q.inflight) > 0 {
+			return false
+		}
+		if !q.resync() {
+			return false
+		}
+	}
+
+	tx := q.pending[0]
+	tx.attempts++
+	retry := tx.attempts < c.BroadcastRetryPolicy.MaxAttempts
+
+	seq := c.NextSequence()
+	txBz, err := c.signTx(q.ctx, tx.msgs, c.AccountNumber, seq, tx.opts...)
+	if err != nil {
+		c.ReleaseSequence(seq)
+		if isSequenceMismatch(err) && retry {
+			q.resyncing = true
+			return true
+		}
+		q.pending = q.pending[1:]
+		tx.future.complete(nil, fmt.Errorf("error creating tx: %w", err))
+		return true
+	}
+
+	resp, err := c.BroadcastTxContext(q.ctx, txBz)
+	if err == nil && resp.TxResponse == nil {
+		err = fmt.Errorf("empty broadcast response")
+	}
+	if err != nil {
+		// The node may or may not have accepted the tx. It is tracked by its hash like an accepted
+		// one, so that it resolves from the chain if it is included and is requeued if it is not,
+		// and nothing else is sent until it settles.
+		hash := fmt.Sprintf("%X", cmttypes.Tx(txBz).Hash())
+		c.logger().Warn("tx broadcast outcome unknown, waiting for inclusion", "tx_hash", hash, "sequence", seq, "error", err)
+		q.pending = q.pending[1:]
+		q.track(tx, seq, hash)
+		q.resyncing = true
+		return true
+	}
+
+	txr := resp.TxResponse
+	if txr.Code != 0 {
+		// A tx rejected by CheckTx does not consume its sequence.
+		c.ReleaseSequence(seq)
+		if isSequenceMismatchCode(txr.Codespace, txr.Code) && retry {
+			q.resyncing = true
+			return true
+		}
+		q.pending = q.pending[1:]
+		tx.future.complete(txr, NewTxFailedError(txr, true))
+		return true
+	}
+
+	q.pending = q.pending[1:]
+	q.track(tx, seq, txr.TxHash)
+	return true
+}
+
+// track adds tx, broadcast with seq under hash, to the in-flight transactions.
+func (q *TxQueue) track(tx *queuedTx, seq uint64, hash string) {
+	tx.seq = seq
+	tx.hash = hash
+	tx.sentAt = time.Now()
+	q.inflight = append(q.inflight, tx)
+}
+
+// poll resolves in-flight transactions that have been included, and requeues everything from the
+// first transaction that timed out.
+func (q *TxQueue) poll() {
+	for i := 0; i < len(q.inflight); {
+		tx := q.inflight[i]
+
+		txr, err := q.c.lookupTx(q.ctx, tx.hash)
+		if err == nil && txr != nil {
+			tx.future.complete(txr, txResultErr(txr))
+			q.inflight = append(q.inflight[:i], q.inflight[i+1:]...)
+			continue
+		}
+
+		if time.Since(tx.sentAt) > q.opts.InclusionTimeout {
+			q.requeueFrom(i)
+			return
+		}
+		i++
+	}
+}
+
+// requeueFrom moves in-flight transactions i and later back to the front of the pending queue.
+// Later transactions cannot be included without transaction i's sequence.
+func (q *TxQueue) requeueFrom(i int) {
+	dropped := append([]*queuedTx(nil), q.inflight[i:]...)
+	q.inflight = q.inflight[:i]
+
+	requeue := make([]*queuedTx, 0, len(dropped))
+	for _, tx := range dropped {
+		if tx.attempts >= q.c.BroadcastRetryPolicy.MaxAttempts {
+			tx.future.complete(nil, fmt.Errorf("tx %s was not included within %s", tx.hash, q.opts.InclusionTimeout))
+			continue
+		}
+		requeue = append(requeue, tx)
+	}
+	q.pending = append(requeue, q.pending...)
+	q.resyncing = true
+
+	q.c.logger().Warn("tx not included in time, requeueing",
+		"tx_hash", dropped[0].hash,
+		"sequence", dropped[0].seq,
+		"requeued", len(requeue),
+		"timeout", q.opts.InclusionTimeout,
+	)
+}
+
+// resync reloads the sequence from the chain once nothing is in flight. Requeued transactions whose
+// sequence the chain has already consumed are resolved instead of being sent twice.
+func (q *TxQueue) resync() bool {
+	_, sequence, err := q.c.ResetSequenceContext(q.ctx)
+	if err != nil {
+		// Try again on the next poll.
+		q.c.logger().Warn("tx queue resync failed", "error", err)
+		return false
+	}
+	q.c.logger().Debug("tx queue resynced", "sequence", sequence, "pending", len(q.pending))
+
+	remaining := q.pending[:0]
+	for _, tx := range q.pending {
+		if tx.hash == "" || tx.seq >= sequence {
+			tx.hash = ""
+			remaining = append(remaining, tx)
+			continue
+		}
+
+		txr, err := q.c.lookupTx(q.ctx, tx.hash)
+		if err == nil && txr != nil {
+			tx.future.complete(txr, txResultErr(txr))
+		} else {
+			tx.future.complete(nil, fmt.Errorf("sequence %d was used on chain but tx %s was not found", tx.seq, tx.hash))
+		}
+	}
+	q.pending = remaining
+	q.resyncing = false
+	return true
+}
+
+// lookupTx returns the included tx for hash, or nil if it has not been indexed yet.
+func (c *ProvenanceClient) lookupTx(ctx context.Context, hash string) (*sdk.TxResponse, error) {
+	txClient := txtypes.NewServiceClient(c.Grpc)
+	resp, err := txClient.GetTx(ctx, &txtypes.GetTxRequest{Hash: hash})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.TxResponse, nil
+}
@@ -0,0 +1,325 @@
+package provenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	cmttypes "github.com/cometbft/cometbft/types"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chainNode is an in-process node for a single account. It checks the sequence of every simulated and
+// broadcast tx against the committed sequence plus its mempool, as CheckTx does, and commits the
+// mempool in a block whenever a tx is looked up.
+type chainNode struct {
+	mu       sync.Mutex
+	address  string
+	sequence uint64
+	mempool  []chainTx
+	included map[string]*sdk.TxResponse
+	// memos are those of the included txs, in the order they were included.
+	memos []string
+	// accepted are the sequences of the txs CheckTx accepted, in order.
+	accepted   []uint64
+	mismatches int
+
+	// drop holds sequences whose tx is dropped from the mempool, once, instead of being included.
+	drop map[uint64]bool
+	// lostReplies is the number of accepted broadcasts answered with UNAVAILABLE.
+	lostReplies int
+	// racers is the number of sequence checks that another client's tx wins, taking the sequence first.
+	racers int
+}
+
+type chainTx struct {
+	hash string
+	memo string
+	seq  uint64
+}
+
+// startChainNode serves a chainNode for the test mnemonic's account, whose committed sequence is
+// sequence, and returns a client signing for the account.
+func startChainNode(t *testing.T, sequence uint64) (*chainNode, *ProvenanceClient) {
+	t.Helper()
+	conf := NewLocalnetConfig()
+	signer, err := NewMnemonicSigner(conf, offlineTestMnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cdc, err := NewCodec(conf.AddressPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &ProvenanceClient{
+		BcConfig:             conf,
+		TxConfig:             authtx.NewTxConfig(cdc, authtx.DefaultSignModes),
+		Signer:               signer,
+		AccountNumber:        1,
+		Sequence:             sequence,
+		BroadcastRetryPolicy: RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond},
+	}
+	c.Address, _ = c.FormatAddress(signer.Address())
+
+	n := &chainNode{address: c.Address, sequence: sequence, included: map[string]*sdk.TxResponse{}, drop: map[uint64]bool{}}
+	c.Grpc = serveTestNode(t, func(srv *grpc.Server) {
+		txtypes.RegisterServiceServer(srv, chainTxService{node: n})
+		authtypes.RegisterQueryServer(srv, chainAuthService{node: n})
+	})
+	return n, c
+}
+
+// sendMsgs returns a bank send from c's account.
+func sendMsgs(c *ProvenanceClient) []sdk.Msg {
+	return []sdk.Msg{&banktypes.MsgSend{FromAddress: c.Address, ToAddress: c.Address, Amount: sdk.NewCoins(sdk.NewInt64Coin("nhash", 1))}}
+}
+
+// fixedTxOptions skip gas and fee estimation.
+func fixedTxOptions(memo string) []TxOption {
+	return []TxOption{WithMemo(memo), WithGasLimit(100000), WithFixedFee(sdk.NewCoins(sdk.NewInt64Coin("nhash", 1)))}
+}
+
+// checkSequence returns the mismatch error CheckTx gives a tx signed with the wrong sequence.
+func (n *chainNode) checkSequence(txBz []byte) (uint64, error) {
+	tx, err := DecodeTx(txBz)
+	if err != nil {
+		return 0, err
+	}
+	sigs, err := tx.GetSignaturesV2()
+	if err != nil || len(sigs) != 1 {
+		return 0, fmt.Errorf("signatures: %v %v", sigs, err)
+	}
+	if n.racers > 0 {
+		n.racers--
+		n.mempool = append(n.mempool, chainTx{hash: fmt.Sprintf("RACER%d", n.racers), seq: n.sequence + uint64(len(n.mempool))})
+	}
+	if want := n.sequence + uint64(len(n.mempool)); sigs[0].Sequence != want {
+		n.mismatches++
+		return 0, fmt.Errorf("account sequence mismatch, expected %d, got %d: incorrect account sequence", want, sigs[0].Sequence)
+	}
+	return sigs[0].Sequence, nil
+}
+
+// block commits the mempool up to the first dropped tx, which evicts the txs after it.
+func (n *chainNode) block() {
+	for len(n.mempool) > 0 {
+		tx := n.mempool[0]
+		if n.drop[tx.seq] {
+			delete(n.drop, tx.seq)
+			n.mempool = nil
+			return
+		}
+		n.mempool = n.mempool[1:]
+		n.sequence++
+		n.included[tx.hash] = &sdk.TxResponse{TxHash: tx.hash, Height: int64(len(n.memos) + 1)}
+		n.memos = append(n.memos, tx.memo)
+	}
+}
+
+type chainTxService struct {
+	*txtypes.UnimplementedServiceServer
+	node *chainNode
+}
+
+func (s chainTxService) Simulate(_ context.Context, req *txtypes.SimulateRequest) (*txtypes.SimulateResponse, error) {
+	n := s.node
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, err := n.checkSequence(req.TxBytes); err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	return &txtypes.SimulateResponse{GasInfo: &sdk.GasInfo{GasUsed: 1000}}, nil
+}
+
+func (s chainTxService) BroadcastTx(_ context.Context, req *txtypes.BroadcastTxRequest) (*txtypes.BroadcastTxResponse, error) {
+	n := s.node
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	seq, err := n.checkSequence(req.TxBytes)
+	if err != nil {
+		return &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{Codespace: "sdk", Code: 32, RawLog: err.Error()}}, nil
+	}
+	tx, _ := DecodeTx(req.TxBytes)
+	hash := fmt.Sprintf("%X", cmttypes.Tx(req.TxBytes).Hash())
+	n.mempool = append(n.mempool, chainTx{hash: hash, memo: tx.GetMemo(), seq: seq})
+	n.accepted = append(n.accepted, seq)
+
+	if n.lostReplies > 0 {
+		n.lostReplies--
+		return nil, status.Error(codes.Unavailable, "connection reset")
+	}
+	return &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: hash}}, nil
+}
+
+func (s chainTxService) GetTx(_ context.Context, req *txtypes.GetTxRequest) (*txtypes.GetTxResponse, error) {
+	n := s.node
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.block()
+	txr, ok := n.included[req.Hash]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "tx %s not found", req.Hash)
+	}
+	return &txtypes.GetTxResponse{TxResponse: txr}, nil
+}
+
+type chainAuthService struct {
+	*authtypes.UnimplementedQueryServer
+	node *chainNode
+}
+
+func (s chainAuthService) Account(context.Context, *authtypes.QueryAccountRequest) (*authtypes.QueryAccountResponse, error) {
+	n := s.node
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	a, err := codectypes.NewAnyWithValue(&authtypes.BaseAccount{Address: n.address, AccountNumber: 1, Sequence: n.sequence})
+	if err != nil {
+		return nil, err
+	}
+	return &authtypes.QueryAccountResponse{Account: a}, nil
+}
+
+// submitAll submits count txs, memoed with their index, to a fast-polling queue, closes it and checks
+// that every tx was included exactly once and in order. It returns the node's accepted sequences.
+func submitAll(t *testing.T, n *chainNode, c *ProvenanceClient, count int) []uint64 {
+	t.Helper()
+	q := c.NewTxQueue(TxQueueOptions{MaxInFlight: 4, PollInterval: 5 * time.Millisecond, InclusionTimeout: 100 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var futures []*TxFuture
+	var want []string
+	for i := 0; i < count; i++ {
+		want = append(want, strconv.Itoa(i))
+		futures = append(futures, q.Submit(sendMsgs(c), fixedTxOptions(want[i])...))
+	}
+	for i, f := range futures {
+		txr, err := f.Wait(ctx)
+		if err != nil || txr == nil || txr.TxHash == "" {
+			t.Fatalf("tx %d: %v, %v", i, txr, err)
+		}
+	}
+	if err := q.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !slices.Equal(n.memos, want) {
+		t.Fatalf("included %v, want %v", n.memos, want)
+	}
+	return slices.Clone(n.accepted)
+}
+
+func TestTxQueueSubmitsInOrder(t *testing.T) {
+	t.Parallel()
+	n, c := startChainNode(t, 5)
+
+	accepted := submitAll(t, n, c, 6)
+	if want := []uint64{5, 6, 7, 8, 9, 10}; !slices.Equal(accepted, want) {
+		t.Fatalf("accepted sequences %v, want %v", accepted, want)
+	}
+}
+
+func TestTxQueueResyncsOnSequenceMismatch(t *testing.T) {
+	t.Parallel()
+	n, c := startChainNode(t, 5)
+	// Another client used the account, so the queue starts out behind.
+	c.Sequence = 3
+
+	accepted := submitAll(t, n, c, 4)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if want := []uint64{5, 6, 7, 8}; !slices.Equal(accepted, want) || n.mismatches == 0 {
+		t.Fatalf("accepted sequences %v with %d mismatches, want %v after a mismatch", accepted, n.mismatches, want)
+	}
+}
+
+func TestTxQueueRequeuesAfterInclusionTimeout(t *testing.T) {
+	t.Parallel()
+	n, c := startChainNode(t, 5)
+	// The second tx never makes it into a block, nor can any tx after it.
+	n.drop[6] = true
+
+	accepted := submitAll(t, n, c, 4)
+	if accepted[0] != 5 || accepted[len(accepted)-1] != 8 || len(accepted) <= 4 {
+		t.Fatalf("accepted sequences %v, want 5 to 8 with the dropped ones resent", accepted)
+	}
+}
+
+func TestTxQueueLostBroadcastReply(t *testing.T) {
+	t.Parallel()
+	n, c := startChainNode(t, 5)
+	// The node takes the first tx but the reply never arrives; it resolves from the chain instead of
+	// failing, and is not sent twice.
+	n.lostReplies = 1
+
+	accepted := submitAll(t, n, c, 3)
+	if want := []uint64{5, 6, 7}; !slices.Equal(accepted, want) {
+		t.Fatalf("accepted sequences %v, want %v", accepted, want)
+	}
+}
+
+func TestTxQueueClose(t *testing.T) {
+	t.Parallel()
+	q := (&ProvenanceClient{BroadcastRetryPolicy: DefaultBroadcastRetryPolicy}).NewTxQueue(TxQueueOptions{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := q.Close(ctx); err != nil {
+		t.Fatalf("close empty queue: %v", err)
+	}
+
+	if _, err := q.Submit(nil).Wait(ctx); !errors.Is(err, ErrTxQueueClosed) {
+		t.Fatalf("submit after close: got %v want %v", err, ErrTxQueueClosed)
+	}
+}
+
+func TestTxQueueRequeueFrom(t *testing.T) {
+	t.Parallel()
+	q := &TxQueue{
+		c:    &ProvenanceClient{BroadcastRetryPolicy: RetryPolicy{MaxAttempts: 2}},
+		opts: DefaultTxQueueOptions,
+	}
+	a := &queuedTx{future: newTxFuture(), attempts: 1, seq: 1}
+	b := &queuedTx{future: newTxFuture(), attempts: 2, seq: 2}
+	c := &queuedTx{future: newTxFuture(), attempts: 1, seq: 3}
+	d := &queuedTx{future: newTxFuture()}
+	q.inflight = []*queuedTx{a, b, c}
+	q.pending = []*queuedTx{d}
+
+	q.requeueFrom(1)
+
+	if len(q.inflight) != 1 || q.inflight[0] != a {
+		t.Fatalf("inflight: got %d txs", len(q.inflight))
+	}
+	if len(q.pending) != 2 || q.pending[0] != c || q.pending[1] != d {
+		t.Fatalf("pending not requeued in order")
+	}
+	select {
+	case <-b.future.Done():
+	default:
+		t.Fatal("tx out of attempts was not failed")
+	}
+	if !q.resyncing {
+		t.Fatal("requeue did not trigger a resync")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/google/uuid"
+	marker "github.com/provenance-io/provenance/x/marker/types"
 	meta "github.com/provenance-io/provenance/x/metadata/types"
 )
 
@@ -109,3 +110,100 @@ func NewJsonRecord(signer, processName, processMethod, contractSpecUuid, inputNa
 
 	return msgWriteRecord
 }
+
+// MarkerSpec describes a marker to create.
+type MarkerSpec struct {
+	// Amount is the marker's denom and the supply it starts with.
+	Amount sdk.Coin
+	// Restricted makes a restricted coin marker, whose holders can only send it with transfer access,
+	// instead of a coin marker.
+	Restricted bool
+	// SupplyFixed keeps the bank supply of the denom equal to the marker's supply.
+	SupplyFixed bool
+	// AllowGovernanceControl lets governance proposals manage the marker.
+	AllowGovernanceControl bool
+	// AllowForcedTransfer lets holders of transfer access move the denom out of any account. Only
+	// restricted markers allow it.
+	AllowForcedTransfer bool
+	// RequiredAttributes are account attributes that let their holders receive a restricted denom
+	// without transfer access.
+	RequiredAttributes []string
+	// AccessList grants the marker's permissions.
+	AccessList []marker.AccessGrant
+	// Manager may update the marker while it is proposed. Empty means the signer.
+	Manager string
+	// UsdMills and Volume set the marker's initial net asset value: Volume units worth UsdMills.
+	UsdMills uint64
+	Volume   uint64
+}
+
+func (s MarkerSpec) markerType() marker.MarkerType {
+	if s.Restricted {
+		return marker.MarkerType_RestrictedCoin
+	}
+	return marker.MarkerType_Coin
+}
+
+func (s MarkerSpec) manager(signer string) string {
+	if s.Manager == "" {
+		return signer
+	}
+	return s.Manager
+}
+
+// NewMarkerAccessGrant grants address the given permissions on a marker.
+func NewMarkerAccessGrant(address string, access ...marker.Access) marker.AccessGrant {
+	return marker.AccessGrant{Address: address, Permissions: access}
+}
+
+// NewAddMarker proposes the marker described by spec, to be finalized and activated later.
+func NewAddMarker(signer string, spec MarkerSpec) *marker.MsgAddMarkerRequest {
+	return &marker.MsgAddMarkerRequest{
+		Amount:                 spec.Amount,
+		Manager:                spec.manager(signer),
+		FromAddress:            signer,
+		Status:                 marker.StatusProposed,
+		MarkerType:             spec.markerType(),
+		AccessList:             spec.AccessList,
+		SupplyFixed:            spec.SupplyFixed,
+		AllowGovernanceControl: spec.AllowGovernanceControl,
+		AllowForcedTransfer:    spec.AllowForcedTransfer,
+		RequiredAttributes:     spec.RequiredAttributes,
+		UsdMills:               spec.UsdMills,
+		Volume:                 spec.Volume,
+	}
+}
+
+// NewAddFinalizeActivateMarker creates the marker described by spec and activates it in one message.
+// spec.AccessList must not be empty.
+func NewAddFinalizeActivateMarker(signer string, spec MarkerSpec) *marker.MsgAddFinalizeActivateMarkerRequest {
+	return &marker.MsgAddFinalizeActivateMarkerRequest{
+		Amount:                 spec.Amount,
+		Manager:                spec.manager(signer),
+		FromAddress:            signer,
+		MarkerType:             spec.markerType(),
+		AccessList:             spec.AccessList,
+		SupplyFixed:            spec.SupplyFixed,
+		AllowGovernanceControl: spec.AllowGovernanceControl,
+		AllowForcedTransfer:    spec.AllowForcedTransfer,
+		RequiredAttributes:     spec.RequiredAttributes,
+		UsdMills:               spec.UsdMills,
+		Volume:                 spec.Volume,
+	}
+}
+
+func NewFinalizeMarker(signer, denom string) *marker.MsgFinalizeRequest {
+	return &marker.MsgFinalizeRequest{Denom: denom, Administrator: signer}
+}
+
+func NewActivateMarker(signer, denom string) *marker.MsgActivateRequest {
+	return &marker.MsgActivateRequest{Denom: denom, Administrator: signer}
+}
+
+func NewCancelMarker(signer, denom string) *marker.MsgCancelRequest {
+	return &marker.MsgCancelRequest{Denom: denom, Administrator: signer}
+}
+
+func NewDeleteMarker(signer, denom string) *marker.MsgDeleteRequest {
+	return &marker.MsgDeleteRequest{Denom: denom, Administrator: signer}
+}
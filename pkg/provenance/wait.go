@@ -0,0 +1,248 @@
+package provenance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultWaitPollInterval is how often WaitOnTx queries GetTx.
+	DefaultWaitPollInterval = 2 * time.Second
+
+	// DefaultWaitTimeout bounds WaitOnTx when neither a timeout option nor a context deadline is given.
+	DefaultWaitTimeout = 20 * time.Second
+)
+
+// WaitOptions controls how WaitOnTx watches for a transaction.
+type WaitOptions struct {
+	// PollInterval is how often GetTx is queried.
+	PollInterval time.Duration
+
+	// Timeout bounds the wait in addition to any deadline on the context. 0 leaves it to the context.
+	// It defaults to DefaultWaitTimeout only when the context has no deadline of its own.
+	Timeout time.Duration
+
+	// SubscribeURL is a CometBFT RPC endpoint, e.g. "https://rpc.provenance.io" or
+	// "ws://localhost:26657/websocket". When set, WaitOnTx also subscribes to the node's Tx events
+	// and returns as soon as the transaction is included instead of waiting for the next poll.
+	SubscribeURL string
+}
+
+// WaitOption configures a single WaitOptions field.
+type WaitOption func(*WaitOptions)
+
+func WithPollInterval(d time.Duration) WaitOption {
+	return func(o *WaitOptions) {
+		o.PollInterval = d
+	}
+}
+
+func WithWaitTimeout(d time.Duration) WaitOption {
+	return func(o *WaitOptions) {
+		o.Timeout = d
+	}
+}
+
+func WithTxSubscription(rpcURL string) WaitOption {
+	return func(o *WaitOptions) {
+		o.SubscribeURL = rpcURL
+	}
+}
+
+// WaitOnTx waits until the transaction with txHash is included in a block and returns it. GetTx is
+// polled every PollInterval, and if a subscription URL is given the node's websocket is watched as
+// well; polling carries on by itself if the subscription cannot be established or drops. The wait
+// ends with an error when ctx is done or the timeout elapses, whichever comes first.
+func (c *ProvenanceClient) WaitOnTx(ctx context.Context, txHash string, opts ...WaitOption) (*txtypes.GetTxResponse, error) {
+	// return an error if there is no tx hash provided
+	if txHash == "" {
+		return nil, fmt.Errorf("no tx hash provided")
+	}
+
+	o := WaitOptions{PollInterval: DefaultWaitPollInterval}
+	if _, ok := ctx.Deadline(); !ok {
+		o.Timeout = DefaultWaitTimeout
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.PollInterval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive, got %s", o.PollInterval)
+	}
+
+	// Cancelling on return tears down the subscription however the wait ends.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if o.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, o.Timeout)
+		defer cancelTimeout()
+	}
+
+	log := c.logger().With("tx_hash", txHash)
+	log.Debug("waiting on tx", "poll_interval", o.PollInterval, "timeout", o.Timeout, "subscribe_url", o.SubscribeURL)
+
+	var events <-chan cmttypes.EventDataTx
+	if o.SubscribeURL != "" {
+		// Subscribe before the first poll so an inclusion between the two is not missed.
+		var err error
+		events, err = subscribeTx(ctx, o.SubscribeURL, txHash)
+		if err != nil {
+			log.Warn("tx subscription failed, polling only", "error", err)
+		}
+	}
+
+	txClient := txtypes.NewServiceClient(c.Grpc)
+	ticker := time.NewTicker(o.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Transient GetTx errors, including NotFound before the tx is indexed, are retried.
+		resp, err := txClient.GetTx(ctx, &txtypes.GetTxRequest{Hash: txHash})
+		if err == nil && resp != nil && resp.TxResponse != nil {
+			log.Info("tx included", "height", resp.TxResponse.Height, "code", resp.TxResponse.Code, "source", "poll")
+			return resp, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Warn("gave up waiting on tx", "error", ctx.Err())
+			return nil, fmt.Errorf("get tx %s: %w", txHash, ctx.Err())
+		case <-ticker.C:
+		case ev, ok := <-events:
+			if !ok {
+				log.Debug("tx subscription closed, polling only")
+				events = nil
+				continue
+			}
+			resp, err := txResponseFromEvent(ev)
+			if err != nil {
+				// Fall back to GetTx for a response we couldn't build ourselves.
+				log.Debug("could not decode tx event", "error", err)
+				continue
+			}
+			log.Info("tx included", "height", resp.TxResponse.Height, "code", resp.TxResponse.Code, "source", "subscription")
+			return resp, nil
+		}
+	}
+}
+
+// subscribeTx subscribes to the Tx event for txHash on the CometBFT websocket at rpcURL. The returned
+// channel receives the event once and is closed when the subscription ends or ctx is done. The
+// connection stays open until ctx is done, when it is unsubscribed and closed, so callers must cancel
+// ctx once they are finished with the channel.
+func subscribeTx(ctx context.Context, rpcURL, txHash string) (<-chan cmttypes.EventDataTx, error) {
+	wsURL, err := websocketURL(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", wsURL, err)
+	}
+
+	query := fmt.Sprintf("%s='%s' AND %s='%s'", cmttypes.EventTypeKey, cmttypes.EventTx, cmttypes.TxHashKey, strings.ToUpper(txHash))
+	req := rpctypes.NewRPCRequest(rpctypes.JSONRPCStringID("wait-on-tx"), "subscribe", json.RawMessage(fmt.Sprintf(`{"query":%q}`, query)))
+	if err := conn.WriteJSON(req); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("subscribe: %w", err)
+	}
+
+	events := make(chan cmttypes.EventDataTx, 1)
+	go func() {
+		<-ctx.Done()
+		// Best effort: the node drops the subscription with the connection anyway.
+		unsub := rpctypes.NewRPCRequest(rpctypes.JSONRPCStringID("wait-on-tx"), "unsubscribe", json.RawMessage(fmt.Sprintf(`{"query":%q}`, query)))
+		conn.SetWriteDeadline(time.Now().Add(time.Second))
+		conn.WriteJSON(unsub)
+		conn.Close()
+	}()
+	go func() {
+		defer close(events)
+
+		for {
+			var resp rpctypes.RPCResponse
+			if err := conn.ReadJSON(&resp); err != nil {
+				return
+			}
+			if resp.Error != nil {
+				return
+			}
+
+			var result coretypes.ResultEvent
+			if err := cmtjson.Unmarshal(resp.Result, &result); err != nil || result.Data == nil {
+				// The subscription acknowledgement has an empty result.
+				continue
+			}
+			if tx, ok := result.Data.(cmttypes.EventDataTx); ok {
+				events <- tx
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// websocketURL turns a CometBFT RPC URL into its websocket endpoint.
+func websocketURL(rpcURL string) (string, error) {
+	u, err := url.Parse(rpcURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid rpc url %q: %w", rpcURL, err)
+	}
+	switch u.Scheme {
+	case "http", "tcp":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	case "ws", "wss":
+	default:
+		return "", fmt.Errorf("invalid rpc url %q: unsupported scheme %q", rpcURL, u.Scheme)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/websocket"
+	}
+	return u.String(), nil
+}
+
+// txResponseFromEvent builds the GetTx response for a Tx event, the same way the node does for GetTx.
+// The block timestamp is not part of the event and is left empty.
+func txResponseFromEvent(ev cmttypes.EventDataTx) (*txtypes.GetTxResponse, error) {
+	decoded, err := NewTxConfig().TxDecoder()(ev.Tx)
+	if err != nil {
+		return nil, fmt.Errorf("decode tx: %w", err)
+	}
+	wrapped, ok := decoded.(interface {
+		AsAny() *codectypes.Any
+		GetProtoTx() *txtypes.Tx
+	})
+	if !ok {
+		return nil, fmt.Errorf("unexpected tx type %T", decoded)
+	}
+
+	res := &coretypes.ResultTx{
+		Hash:     cmttypes.Tx(ev.Tx).Hash(),
+		Height:   ev.Height,
+		Index:    ev.Index,
+		TxResult: ev.Result,
+		Tx:       ev.Tx,
+	}
+	return &txtypes.GetTxResponse{
+		Tx:         wrapped.GetProtoTx(),
+		TxResponse: sdk.NewResponseResultTx(res, wrapped.AsAny(), ""),
+	}, nil
+}
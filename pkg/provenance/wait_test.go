@@ -0,0 +1,146 @@
+package provenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// unreachableClient returns a client whose GetTx calls always fail, so only the websocket can resolve a wait.
+func unreachableClient(t *testing.T) *ProvenanceClient {
+	t.Helper()
+	conn, err := grpc.NewClient("passthrough:///127.0.0.1:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &ProvenanceClient{Grpc: &GRPCConnection{Conn: conn}}
+}
+
+func testTxBytes(t *testing.T) []byte {
+	t.Helper()
+	txConfig := NewTxConfig()
+	txBuilder := txConfig.NewTxBuilder()
+	from := sdk.AccAddress(make([]byte, 20))
+	msg := banktypes.NewMsgSend(from, from, sdk.NewCoins(sdk.NewInt64Coin("nhash", 1)))
+	if err := txBuilder.SetMsgs(msg); err != nil {
+		t.Fatalf("set msgs: %v", err)
+	}
+	txBz, err := txConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		t.Fatalf("encode tx: %v", err)
+	}
+	return txBz
+}
+
+func TestWaitOnTxSubscription(t *testing.T) {
+	t.Parallel()
+	txBz := testTxBytes(t)
+	hash := fmt.Sprintf("%X", cmttypes.Tx(txBz).Hash())
+
+	upgrader := websocket.Upgrader{}
+	released := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/websocket" {
+			http.NotFound(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var req rpctypes.RPCRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		if req.Method != "subscribe" || !strings.Contains(string(req.Params), hash) {
+			conn.WriteJSON(rpctypes.RPCInvalidRequestError(req.ID, errors.New("unexpected request")))
+			return
+		}
+		conn.WriteJSON(rpctypes.NewRPCSuccessResponse(req.ID, &coretypes.ResultSubscribe{}))
+		conn.WriteJSON(rpctypes.NewRPCSuccessResponse(req.ID, &coretypes.ResultEvent{
+			Query: "tm.event='Tx'",
+			Data: cmttypes.EventDataTx{TxResult: abci.TxResult{
+				Height: 42,
+				Tx:     txBz,
+				Result: abci.ExecTxResult{GasUsed: 1000, GasWanted: 2000},
+			}},
+		}))
+		// Hold the connection open until the client is done with it.
+		var unsub rpctypes.RPCRequest
+		conn.ReadJSON(&unsub)
+		released <- unsub.Method
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := unreachableClient(t).WaitOnTx(ctx, hash, WithPollInterval(time.Minute), WithTxSubscription(srv.URL))
+	if err != nil {
+		t.Fatalf("wait on tx: %v", err)
+	}
+	txr := resp.TxResponse
+	if txr.TxHash != hash || txr.Height != 42 || txr.GasUsed != 1000 || txr.Code != 0 {
+		t.Fatalf("unexpected tx response: %+v", txr)
+	}
+	if resp.Tx == nil || len(resp.Tx.Body.Messages) != 1 {
+		t.Fatalf("tx not decoded: %+v", resp.Tx)
+	}
+
+	// The subscription is torn down as soon as WaitOnTx returns, not when ctx expires.
+	select {
+	case method := <-released:
+		if method != "unsubscribe" {
+			t.Fatalf("got %q before close want unsubscribe", method)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("websocket still open after WaitOnTx returned")
+	}
+}
+
+func TestWaitOnTxContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := unreachableClient(t).WaitOnTx(ctx, "ABCD", WithPollInterval(10*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWebsocketURL(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"http://localhost:26657":           "ws://localhost:26657/websocket",
+		"https://rpc.example.com":          "wss://rpc.example.com/websocket",
+		"tcp://127.0.0.1:26657":            "ws://127.0.0.1:26657/websocket",
+		"ws://localhost:26657/websocket":   "ws://localhost:26657/websocket",
+		"wss://rpc.example.com/custom/ws/": "wss://rpc.example.com/custom/ws/",
+	}
+	for in, want := range cases {
+		got, err := websocketURL(in)
+		if err != nil || got != want {
+			t.Fatalf("%s: got %q, %v want %q", in, got, err, want)
+		}
+	}
+	if _, err := websocketURL("grpc://localhost:9090"); err == nil {
+		t.Fatal("expected error for unsupported scheme")
+	}
+}